@@ -0,0 +1,296 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed возвращается при записи в уже закрытый writer или handler
+var ErrClosed = errors.New("logger: closed")
+
+// OverflowPolicy определяет поведение AsyncWriter при переполненной очереди
+type OverflowPolicy int
+
+const (
+	// Block ждет, пока фоновая горутина освободит место в очереди
+	Block OverflowPolicy = iota
+	// DropNewest отбрасывает новую строку, очередь не меняется
+	DropNewest
+	// DropOldest вытесняет самую старую строку из очереди
+	DropOldest
+)
+
+// AsyncOptions настраивает AsyncWriter
+type AsyncOptions struct {
+	// QueueSize - емкость очереди в строках (по умолчанию 1024)
+	QueueSize int
+	// Policy - поведение при переполнении очереди (по умолчанию Block)
+	Policy OverflowPolicy
+	// ReportInterval - как часто сообщать об отброшенных строках
+	// (по умолчанию 10s, отрицательное значение отключает отчеты)
+	ReportInterval time.Duration
+	// Reporter - логгер для отчетов об отброшенных строках. По умолчанию
+	// ColorHandler, пишущий напрямую в исходный writer. Не должен писать
+	// в сам AsyncWriter.
+	Reporter *slog.Logger
+}
+
+// asyncItem - строка лога с порядковым номером постановки в очередь
+type asyncItem struct {
+	buf *buffer
+	seq uint64
+}
+
+// flushRequest - ожидающий Flush: ответ отправляется в done, когда
+// записаны (или вытеснены) все строки с номерами до seq
+type flushRequest struct {
+	seq  uint64
+	done chan error
+}
+
+// AsyncWriter передает отформатированные строки фоновой горутине через
+// ограниченную очередь, чтобы медленный writer не блокировал вызывающих.
+//
+//	w := logger.NewAsyncWriter(conn, logger.AsyncOptions{Policy: logger.DropOldest})
+//	defer w.Close()
+//	log := slog.New(logger.NewColorHandler(w))
+type AsyncWriter struct {
+	w        io.Writer
+	policy   OverflowPolicy
+	reporter *slog.Logger
+	interval time.Duration
+
+	queue chan asyncItem
+	wake  chan struct{} // новый запрос Flush
+	done  chan struct{} // закрывается при выходе фоновой горутины
+
+	mu     sync.Mutex // постановка в очередь и нумерация против закрытия
+	seq    uint64     // номер последней строки, поставленной в очередь
+	closed bool
+
+	flushMu sync.Mutex // отдельно от mu: фоновая горутина не ждет Write
+	flushes []flushRequest
+	written uint64 // номер последней записанной строки; только фоновая горутина
+
+	dropped atomic.Uint64 // отброшено с момента последнего отчета
+	total   atomic.Uint64 // отброшено за все время
+
+	errMu sync.Mutex
+	err   error // первая ошибка записи с момента последнего Flush
+}
+
+// NewAsyncWriter создает AsyncWriter и запускает фоновую горутину записи в w
+func NewAsyncWriter(w io.Writer, opts AsyncOptions) *AsyncWriter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.ReportInterval == 0 {
+		opts.ReportInterval = 10 * time.Second
+	}
+	if opts.Reporter == nil {
+		opts.Reporter = slog.New(NewColorHandler(w))
+	}
+
+	a := &AsyncWriter{
+		w:        w,
+		policy:   opts.Policy,
+		reporter: opts.Reporter,
+		interval: opts.ReportInterval,
+		queue:    make(chan asyncItem, opts.QueueSize),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Write копирует p в буфер из пула и ставит его в очередь.
+// При политиках Drop* никогда не блокируется.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	buf := newBuffer()
+	buf.Write(p)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		buf.Free()
+		return 0, ErrClosed
+	}
+
+	// Номера идут в порядке очереди: отправка выполняется под a.mu
+	item := asyncItem{buf: buf, seq: a.seq + 1}
+	switch a.policy {
+	case DropNewest:
+		select {
+		case a.queue <- item:
+		default:
+			a.drop(item)
+			return len(p), nil
+		}
+	case DropOldest:
+		select {
+		case a.queue <- item:
+		default:
+			// Очередь пополняет только Write под a.mu, поэтому место,
+			// освобожденное вытеснением, никто не займет
+			select {
+			case old := <-a.queue:
+				a.drop(old)
+			default:
+			}
+			a.queue <- item
+		}
+	default:
+		a.queue <- item
+	}
+	a.seq = item.seq
+	return len(p), nil
+}
+
+// drop учитывает отброшенную строку
+func (a *AsyncWriter) drop(item asyncItem) {
+	item.buf.Free()
+	a.dropped.Add(1)
+	a.total.Add(1)
+}
+
+// Dropped возвращает общее число отброшенных строк
+func (a *AsyncWriter) Dropped() uint64 {
+	return a.total.Load()
+}
+
+// Flush ждет записи всех строк, поставленных в очередь до вызова
+// (кроме вытесненных), и возвращает первую ошибку записи с момента
+// предыдущего Flush. Очередь Flush не занимает, поэтому не мешает Write.
+func (a *AsyncWriter) Flush() error {
+	req := flushRequest{done: make(chan error, 1)}
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	req.seq = a.seq
+	// Регистрируем под a.mu: до закрытия, поэтому горутина ответит и при Close
+	a.flushMu.Lock()
+	a.flushes = append(a.flushes, req)
+	a.flushMu.Unlock()
+	a.mu.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default: // горутина уже разбудена
+	}
+	return <-req.done
+}
+
+// Close дописывает очередь, сообщает об отброшенных строках,
+// останавливает фоновую горутину и закрывает исходный writer
+// (кроме os.Stdout и os.Stderr). Повторный вызов ничего не делает.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	<-a.done
+	return errors.Join(a.takeErr(), closeWriter(a.w))
+}
+
+// run - фоновая горутина: пишет строки и периодически сообщает о потерях
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+
+	var tick <-chan time.Time
+	if a.interval > 0 {
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case item, ok := <-a.queue:
+			if !ok {
+				a.report()
+				a.answerFlushes(true)
+				a.setErr(flushWriter(a.w))
+				return
+			}
+			_, err := a.w.Write(*item.buf)
+			a.setErr(err)
+			item.buf.Free()
+			a.written = item.seq
+			a.answerFlushes(false)
+		case <-a.wake:
+			a.answerFlushes(false)
+		case <-tick:
+			a.report()
+		}
+	}
+}
+
+// answerFlushes сбрасывает writer и отвечает запросам Flush, строки
+// которых уже записаны, а при all - всем. Строка, вытесненная из очереди,
+// ответа не задерживает: за ней в очереди есть строки с большими номерами.
+func (a *AsyncWriter) answerFlushes(all bool) {
+	a.flushMu.Lock()
+	var ready []flushRequest
+	pending := a.flushes[:0]
+	for _, req := range a.flushes {
+		if all || req.seq <= a.written {
+			ready = append(ready, req)
+		} else {
+			pending = append(pending, req)
+		}
+	}
+	a.flushes = pending
+	a.flushMu.Unlock()
+
+	if len(ready) == 0 {
+		return
+	}
+	a.setErr(flushWriter(a.w))
+	err := a.takeErr()
+	for _, req := range ready {
+		req.done <- err
+	}
+}
+
+// report логирует число строк, отброшенных с прошлого отчета
+func (a *AsyncWriter) report() {
+	if a.interval < 0 {
+		return
+	}
+	if n := a.dropped.Swap(0); n > 0 {
+		a.reporter.Warn("async writer dropped log lines", "dropped", n, "total", a.total.Load())
+	}
+}
+
+func (a *AsyncWriter) setErr(err error) {
+	if err == nil {
+		return
+	}
+	a.errMu.Lock()
+	if a.err == nil {
+		a.err = err
+	}
+	a.errMu.Unlock()
+}
+
+func (a *AsyncWriter) takeErr() error {
+	a.errMu.Lock()
+	defer a.errMu.Unlock()
+	err := a.err
+	a.err = nil
+	return err
+}
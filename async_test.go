@@ -0,0 +1,262 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ──────────────────────────────────────────────────────────
+// Хелперы
+// ──────────────────────────────────────────────────────────
+
+// gateWriter блокирует запись, пока не закрыт gate, и сигналит о первой
+// попытке записи через started
+type gateWriter struct {
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	g.once.Do(func() { close(g.started) })
+	<-g.gate
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.Write(p)
+}
+
+func (g *gateWriter) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.String()
+}
+
+// fillQueue пишет первую строку (которую горутина заберет и повиснет на gate),
+// затем заполняет очередь строками line-0..line-(n-1)
+func fillQueue(t *testing.T, a *AsyncWriter, g *gateWriter, n int) {
+	t.Helper()
+	fmt.Fprintln(a, "first")
+	<-g.started
+	for i := range n {
+		fmt.Fprintf(a, "line-%d\n", i)
+	}
+}
+
+// ──────────────────────────────────────────────────────────
+// AsyncWriter
+// ──────────────────────────────────────────────────────────
+
+func TestAsyncWriter_PreservesOrder(t *testing.T) {
+	var out bytes.Buffer
+	a := NewAsyncWriter(&out, AsyncOptions{QueueSize: 4})
+
+	h := NewColorHandler(a)
+	for i := range 100 {
+		_ = h.Handle(t.Context(), newTestRecord(slog.LevelInfo, fmt.Sprintf("msg-%d", i)))
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("ожидалось 100 строк, получено %d", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("msg-%d", i)) {
+			t.Fatalf("строка %d не на своем месте: %q", i, line)
+		}
+	}
+}
+
+func TestAsyncWriter_DropNewest(t *testing.T) {
+	g := newGateWriter()
+	a := NewAsyncWriter(g, AsyncOptions{QueueSize: 2, Policy: DropNewest, ReportInterval: -1})
+
+	fillQueue(t, a, g, 5)
+	if got := a.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, ожидалось 3", got)
+	}
+
+	close(g.gate)
+	_ = a.Close()
+
+	if got, want := g.String(), "first\nline-0\nline-1\n"; got != want {
+		t.Errorf("вывод = %q, ожидалось %q", got, want)
+	}
+}
+
+func TestAsyncWriter_DropOldest(t *testing.T) {
+	g := newGateWriter()
+	a := NewAsyncWriter(g, AsyncOptions{QueueSize: 2, Policy: DropOldest, ReportInterval: -1})
+
+	fillQueue(t, a, g, 5)
+	if got := a.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, ожидалось 3", got)
+	}
+
+	close(g.gate)
+	_ = a.Close()
+
+	if got, want := g.String(), "first\nline-3\nline-4\n"; got != want {
+		t.Errorf("вывод = %q, ожидалось %q", got, want)
+	}
+}
+
+func TestAsyncWriter_ReportsDropped(t *testing.T) {
+	g := newGateWriter()
+	var report bytes.Buffer
+	a := NewAsyncWriter(g, AsyncOptions{
+		QueueSize: 1,
+		Policy:    DropNewest,
+		Reporter:  slog.New(NewColorHandler(&report)),
+	})
+
+	fillQueue(t, a, g, 3)
+	close(g.gate)
+	_ = a.Close()
+
+	out := report.String()
+	if !strings.Contains(out, "dropped log lines") || !strings.Contains(out, "dropped=2") {
+		t.Errorf("отчет об отброшенных строках не найден: %q", out)
+	}
+}
+
+func TestAsyncWriter_Flush(t *testing.T) {
+	g := newGateWriter()
+	a := NewAsyncWriter(g, AsyncOptions{})
+	defer a.Close()
+
+	fmt.Fprintln(a, "pending")
+
+	flushed := make(chan error)
+	go func() { flushed <- a.Flush() }()
+
+	select {
+	case <-flushed:
+		t.Fatal("Flush вернулся до записи строки")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(g.gate)
+	if err := <-flushed; err != nil {
+		t.Fatalf("Flush вернул ошибку: %v", err)
+	}
+	if g.String() != "pending\n" {
+		t.Errorf("после Flush строка не записана: %q", g.String())
+	}
+}
+
+func TestAsyncWriter_DropOldestKeepsFlush(t *testing.T) {
+	g := newGateWriter()
+	w := &flushGateWriter{gateWriter: g}
+	a := NewAsyncWriter(w, AsyncOptions{QueueSize: 2, Policy: DropOldest, ReportInterval: -1})
+	defer a.Close()
+
+	fillQueue(t, a, g, 2)
+	flushed := make(chan error)
+	go func() { flushed <- a.Flush() }()
+	waitFlushes(t, a, 1)
+
+	// Writer завис, очередь полна, Flush ждет: Write все равно не блокируется,
+	// а вытеснение строк не отпускает Flush раньше времени
+	wrote := make(chan struct{})
+	go func() {
+		for i := range 5 {
+			fmt.Fprintf(a, "late-%d\n", i)
+		}
+		close(wrote)
+	}()
+	select {
+	case <-wrote:
+	case <-time.After(time.Second):
+		t.Fatal("Write при DropOldest заблокирован зависшим writer'ом")
+	}
+	select {
+	case err := <-flushed:
+		t.Fatalf("Flush вернулся (%v) до записи строк", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(g.gate)
+	if err := <-flushed; err != nil {
+		t.Fatalf("Flush вернул ошибку: %v", err)
+	}
+	if w.flushes.Load() == 0 {
+		t.Error("Flush должен сбросить writer")
+	}
+	if !strings.HasPrefix(g.String(), "first\n") {
+		t.Errorf("строка, взятая горутиной, должна быть записана до возврата Flush: %q", g.String())
+	}
+}
+
+// waitFlushes ждет, пока зарегистрируются n запросов Flush
+func waitFlushes(t *testing.T, a *AsyncWriter, n int) {
+	t.Helper()
+	for {
+		a.flushMu.Lock()
+		got := len(a.flushes)
+		a.flushMu.Unlock()
+		if got >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// flushGateWriter - gateWriter, считающий вызовы Flush
+type flushGateWriter struct {
+	*gateWriter
+	flushes atomic.Int32
+}
+
+func (w *flushGateWriter) Flush() error {
+	w.flushes.Add(1)
+	return nil
+}
+
+func TestAsyncWriter_FlushReturnsWriteError(t *testing.T) {
+	a := NewAsyncWriter(errWriter{}, AsyncOptions{})
+	defer a.Close()
+
+	fmt.Fprintln(a, "line")
+	if err := a.Flush(); !errors.Is(err, errWrite) {
+		t.Errorf("Flush() = %v, ожидалось %v", err, errWrite)
+	}
+	if err := a.Flush(); err != nil {
+		t.Errorf("повторный Flush() = %v, ожидалось nil", err)
+	}
+}
+
+func TestAsyncWriter_WriteAfterClose(t *testing.T) {
+	a := NewAsyncWriter(&bytes.Buffer{}, AsyncOptions{})
+	_ = a.Close()
+
+	if _, err := a.Write([]byte("late\n")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write после Close = %v, ожидалось ErrClosed", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("повторный Close вернул ошибку: %v", err)
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
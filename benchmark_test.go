@@ -0,0 +1,370 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ──────────────────────────────────────────────────────────
+// Прежняя реализация (color.New + Fprintf) для сравнения
+// ──────────────────────────────────────────────────────────
+
+// legacyHandle повторяет путь отрисовки до перехода на готовые
+// ANSI-последовательности; используется только в бенчмарках и
+// для проверки, что вывод не изменился.
+func legacyHandle(w io.Writer, h *ColorHandler, r slog.Record) error {
+	var levelColor, msgColor *color.Color
+	var levelStr string
+
+	switch r.Level {
+	case slog.LevelDebug:
+		levelColor, msgColor, levelStr = color.New(color.FgHiCyan), color.New(color.FgHiCyan), "DBG"
+	case slog.LevelInfo:
+		levelColor, msgColor, levelStr = color.New(color.FgGreen), color.New(color.FgGreen), "INF"
+	case slog.LevelWarn:
+		levelColor, msgColor, levelStr = color.New(color.FgHiYellow), color.New(color.FgHiWhite), "WRN"
+	case slog.LevelError:
+		levelColor, msgColor, levelStr = color.New(color.FgHiRed), color.New(color.FgHiWhite), "ERR"
+	default:
+		levelColor, msgColor, levelStr = color.New(color.FgWhite), color.New(color.FgHiWhite), "???"
+	}
+
+	buf := newBuffer()
+	defer buf.Free()
+
+	color.New(color.FgHiBlue).Fprintf(buf, "[%s] ", r.Time.Format(time.TimeOnly))
+	levelColor.Fprintf(buf, "%-3s ", levelStr)
+	for _, group := range h.groups {
+		color.New(color.FgHiBlue).Fprintf(buf, "%s.", group)
+	}
+	msgColor.Fprintf(buf, "%s", r.Message)
+
+	var attr func(a slog.Attr)
+	attr = func(a slog.Attr) {
+		if a.Value.Kind() == slog.KindGroup {
+			for _, ga := range a.Value.Group() {
+				attr(ga)
+			}
+			return
+		}
+		color.New(color.FgHiGreen).Fprintf(buf, " %s=", a.Key)
+		color.New(color.FgHiYellow).Fprintf(buf, "%v", formatValue(a.Value, DefaultJSONSniffLimit, nil))
+	}
+	for _, a := range h.attrs {
+		attr(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		attr(a)
+		return true
+	})
+
+	io.WriteString(buf, "\n")
+	_, err := w.Write(*buf)
+	return err
+}
+
+// withColors включает цвета на время теста или бенчмарка
+func withColors(tb testing.TB) {
+	tb.Helper()
+	prev := color.NoColor
+	color.NoColor = false
+	tb.Cleanup(func() { color.NoColor = prev })
+}
+
+// benchRecord создает типичную запись с несколькими атрибутами
+func benchRecord() slog.Record {
+	r := newTestRecord(slog.LevelInfo, "request handled")
+	r.AddAttrs(
+		slog.String("method", "GET"),
+		slog.String("path", "/api/users"),
+		slog.Int("status", 200),
+		slog.Float64("ratio", 0.75),
+		slog.Bool("cached", true),
+		slog.Duration("elapsed", 1200*time.Microsecond),
+	)
+	return r
+}
+
+func TestHandle_MatchesLegacyOutput(t *testing.T) {
+	withColors(t)
+
+	h, buf := newTestHandler()
+	h2 := h.WithGroup("http").WithAttrs([]slog.Attr{slog.String("service", "api")}).(*ColorHandler)
+
+	for _, lvl := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError, slog.Level(42)} {
+		buf.Reset()
+		r := benchRecord()
+		r.Level = lvl
+		r.AddAttrs(slog.Group("user", slog.String("name", "Alice"), slog.Int("id", 7)))
+
+		if err := h2.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle вернул ошибку: %v", err)
+		}
+		got := buf.String()
+
+		buf.Reset()
+		if err := legacyHandle(buf, h2, r); err != nil {
+			t.Fatalf("legacyHandle вернул ошибку: %v", err)
+		}
+		if want := buf.String(); got != want {
+			t.Errorf("уровень %v: вывод отличается\n got: %q\nwant: %q", lvl, got, want)
+		}
+	}
+
+	// JSON-значения теперь подсвечиваются по лексемам (KindAny со структурой,
+	// JSON-строкой и т.п.): цвета отличаются намеренно, текст - нет
+	buf.Reset()
+	r := benchRecord()
+	r.AddAttrs(slog.Any("user", struct {
+		Name string `json:"name"`
+		ID   int    `json:"id"`
+	}{"Alice", 7}))
+	if err := h2.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle вернул ошибку: %v", err)
+	}
+	got := buf.String()
+
+	buf.Reset()
+	if err := legacyHandle(buf, h2, r); err != nil {
+		t.Fatalf("legacyHandle вернул ошибку: %v", err)
+	}
+	if want := buf.String(); stripANSIString(got) != stripANSIString(want) {
+		t.Errorf("текст JSON-значения отличается\n got: %q\nwant: %q", got, want)
+	}
+	if !strings.Contains(got, string(jsonKeyStyle)+`"name"`) {
+		t.Errorf("JSON-значение должно подсвечиваться по лексемам: %q", got)
+	}
+}
+
+// ──────────────────────────────────────────────────────────
+// Бенчмарки: текущая реализация, прежняя и slog.TextHandler
+// ──────────────────────────────────────────────────────────
+
+func BenchmarkHandle(b *testing.B) {
+	withColors(b)
+	ctx := context.Background()
+	r := benchRecord()
+
+	b.Run("ColorHandler", func(b *testing.B) {
+		h := NewColorHandler(io.Discard)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = h.Handle(ctx, r)
+		}
+	})
+
+	b.Run("Legacy", func(b *testing.B) {
+		h := NewColorHandler(io.Discard)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = legacyHandle(io.Discard, h, r)
+		}
+	})
+
+	b.Run("TextHandler", func(b *testing.B) {
+		h := slog.NewTextHandler(io.Discard, nil)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = h.Handle(ctx, r)
+		}
+	})
+}
+
+func BenchmarkHandle_WithAttrs(b *testing.B) {
+	withColors(b)
+	ctx := context.Background()
+	r := benchRecord()
+
+	attrs := make([]slog.Attr, 10)
+	for i := range attrs {
+		attrs[i] = slog.String(fmt.Sprintf("key%d", i), "value")
+	}
+
+	b.Run("ColorHandler", func(b *testing.B) {
+		h := NewColorHandler(io.Discard).WithAttrs(attrs)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = h.Handle(ctx, r)
+		}
+	})
+
+	b.Run("Legacy", func(b *testing.B) {
+		h := NewColorHandler(io.Discard).WithAttrs(attrs).(*ColorHandler)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = legacyHandle(io.Discard, h, r)
+		}
+	})
+
+	b.Run("TextHandler", func(b *testing.B) {
+		h := slog.NewTextHandler(io.Discard, nil).WithAttrs(attrs)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = h.Handle(ctx, r)
+		}
+	})
+}
+
+// ──────────────────────────────────────────────────────────
+// Набор бенчмарков ColorHandler.Handle
+// ──────────────────────────────────────────────────────────
+
+type benchOrder struct {
+	ID       int      `json:"id"`
+	Customer string   `json:"customer"`
+	Items    []string `json:"items"`
+	Total    float64  `json:"total"`
+}
+
+// manyAttrs возвращает n разнотипных атрибутов
+func manyAttrs(n int) []slog.Attr {
+	attrs := make([]slog.Attr, n)
+	for i := range attrs {
+		key := fmt.Sprintf("key%d", i)
+		switch i % 4 {
+		case 0:
+			attrs[i] = slog.String(key, "value")
+		case 1:
+			attrs[i] = slog.Int(key, i)
+		case 2:
+			attrs[i] = slog.Bool(key, i%3 == 0)
+		default:
+			attrs[i] = slog.Duration(key, time.Duration(i)*time.Millisecond)
+		}
+	}
+	return attrs
+}
+
+// handleCase описывает один сценарий для бенчмарков и проверки аллокаций
+type handleCase struct {
+	name      string
+	handler   func() slog.Handler
+	record    func() slog.Record
+	maxAllocs float64 // допустимое число аллокаций на вызов Handle
+}
+
+func handleCases() []handleCase {
+	return []handleCase{
+		{
+			name:    "Simple",
+			handler: func() slog.Handler { return NewColorHandler(io.Discard) },
+			record:  func() slog.Record { return newTestRecord(slog.LevelInfo, "server started") },
+		},
+		{
+			name:    "ManyAttrs",
+			handler: func() slog.Handler { return NewColorHandler(io.Discard) },
+			record: func() slog.Record {
+				r := newTestRecord(slog.LevelInfo, "many attrs")
+				r.AddAttrs(manyAttrs(20)...)
+				return r
+			},
+		},
+		{
+			name: "WithAttrs",
+			handler: func() slog.Handler {
+				return NewColorHandler(io.Discard).
+					WithAttrs(manyAttrs(10)).
+					WithGroup("http").
+					WithAttrs(manyAttrs(10))
+			},
+			record: benchRecord,
+		},
+		{
+			name:    "NestedGroups",
+			handler: func() slog.Handler { return NewColorHandler(io.Discard).WithGroup("a").WithGroup("b") },
+			record: func() slog.Record {
+				r := newTestRecord(slog.LevelWarn, "nested")
+				r.AddAttrs(slog.Group("request",
+					slog.String("method", "POST"),
+					slog.Group("user",
+						slog.Int("id", 7),
+						slog.Group("session", slog.String("id", "s-1"), slog.Bool("fresh", true)),
+					),
+				))
+				return r
+			},
+		},
+		{
+			name:    "Struct",
+			handler: func() slog.Handler { return NewColorHandler(io.Discard) },
+			record: func() slog.Record {
+				r := newTestRecord(slog.LevelInfo, "order")
+				r.AddAttrs(slog.Any("order", benchOrder{ID: 1, Customer: "Alice", Items: []string{"book", "pen"}, Total: 9.5}))
+				return r
+			},
+			// json.MarshalIndent и упаковка результата в interface{}
+			maxAllocs: 8,
+		},
+	}
+}
+
+func BenchmarkColorHandler(b *testing.B) {
+	withColors(b)
+	ctx := context.Background()
+
+	for _, tc := range handleCases() {
+		b.Run(tc.name, func(b *testing.B) {
+			h, r := tc.handler(), tc.record()
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = h.Handle(ctx, r)
+			}
+		})
+	}
+
+	b.Run("Parallel", func(b *testing.B) {
+		h := NewColorHandler(io.Discard).WithAttrs(manyAttrs(4))
+		r := benchRecord()
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = h.Handle(ctx, r)
+			}
+		})
+	})
+}
+
+// ──────────────────────────────────────────────────────────
+// Защита от регрессий по аллокациям
+// ──────────────────────────────────────────────────────────
+
+func TestHandle_Allocs(t *testing.T) {
+	if testing.Short() || raceEnabled {
+		t.Skip("замеры аллокаций недостоверны в коротком режиме и под race")
+	}
+	withColors(t)
+	ctx := context.Background()
+
+	for _, tc := range handleCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			h, r := tc.handler(), tc.record()
+			got := testing.AllocsPerRun(100, func() {
+				_ = h.Handle(ctx, r)
+			})
+			if got > tc.maxAllocs {
+				t.Errorf("Handle: %v аллокаций на вызов, допустимо не более %v", got, tc.maxAllocs)
+			}
+		})
+	}
+}
+
+func BenchmarkIsJSON(b *testing.B) {
+	text := strings.Repeat("plain log text ", 1000)
+	obj := `{"items":[` + strings.Repeat(`{"id":1,"name":"x"},`, 500) + `{}]}`
+
+	for _, bc := range []struct{ name, s string }{{"Text", text}, {"Object", obj}} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bc.s)))
+			for b.Loop() {
+				isJSON(bc.s, DefaultJSONSniffLimit)
+			}
+		})
+	}
+}
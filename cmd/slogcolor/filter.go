@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filter отбирает разобранные записи до вывода через ColorHandler.
+// Нулевое значение пропускает все записи.
+type filter struct {
+	minLevel    slog.Level
+	hasMinLevel bool
+	msg         *regexp.Regexp
+	conds       []condition
+	since       time.Time
+	until       time.Time
+}
+
+// match проверяет запись по всем критериям
+func (f *filter) match(r slog.Record) bool {
+	if f.hasMinLevel && r.Level < f.minLevel {
+		return false
+	}
+	if f.msg != nil && !f.msg.MatchString(r.Message) {
+		return false
+	}
+	if !f.since.IsZero() || !f.until.IsZero() {
+		if r.Time.IsZero() {
+			return false
+		}
+		if !f.since.IsZero() && r.Time.Before(f.since) {
+			return false
+		}
+		if !f.until.IsZero() && r.Time.After(f.until) {
+			return false
+		}
+	}
+	for _, c := range f.conds {
+		if !c.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ──────────────────────────────────────────────────────────
+// Условия на атрибуты
+// ──────────────────────────────────────────────────────────
+
+// condition - условие вида key=value, status>=500, path~^/api
+type condition struct {
+	key   string
+	op    string
+	value string
+	re    *regexp.Regexp // для оператора ~
+}
+
+// operators перечислены так, чтобы двухсимвольные проверялись первыми
+var operators = []string{">=", "<=", "!=", "=", ">", "<", "~"}
+
+// parseCondition разбирает условие -where
+func parseCondition(s string) (condition, error) {
+	for i := 0; i < len(s); i++ {
+		for _, op := range operators {
+			if !strings.HasPrefix(s[i:], op) {
+				continue
+			}
+			c := condition{key: strings.TrimSpace(s[:i]), op: op, value: strings.TrimSpace(s[i+len(op):])}
+			if c.key == "" {
+				return condition{}, fmt.Errorf("условие %q: пустой ключ", s)
+			}
+			if op == "~" {
+				re, err := regexp.Compile(c.value)
+				if err != nil {
+					return condition{}, fmt.Errorf("условие %q: %w", s, err)
+				}
+				c.re = re
+			}
+			return c, nil
+		}
+	}
+	return condition{}, fmt.Errorf("условие %q: ожидается оператор (%s)", s, strings.Join(operators, " "))
+}
+
+// match проверяет условие; запись без атрибута проходит только для !=
+func (c condition) match(r slog.Record) bool {
+	v, ok := lookupAttr(r, c.key)
+	if !ok {
+		return c.op == "!="
+	}
+	if c.re != nil {
+		return c.re.MatchString(valueString(v))
+	}
+
+	cmp, ok := compareValue(v, c.value)
+	if !ok {
+		// Несравнимые значения: только проверка на (не)равенство строк
+		switch c.op {
+		case "=":
+			return valueString(v) == c.value
+		case "!=":
+			return valueString(v) != c.value
+		}
+		return false
+	}
+
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// lookupAttr ищет атрибут по ключу; "user.id" ищется и как плоский ключ
+// (logfmt), и как путь по группам (JSON)
+func lookupAttr(r slog.Record, key string) (slog.Value, bool) {
+	var found slog.Value
+	var ok bool
+	r.Attrs(func(a slog.Attr) bool {
+		found, ok = lookupIn(a, key)
+		return !ok
+	})
+	return found, ok
+}
+
+func lookupIn(a slog.Attr, key string) (slog.Value, bool) {
+	if a.Key == key {
+		return a.Value, true
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return slog.Value{}, false
+	}
+	rest, ok := strings.CutPrefix(key, a.Key+".")
+	if !ok {
+		return slog.Value{}, false
+	}
+	for _, ga := range a.Value.Group() {
+		if v, ok := lookupIn(ga, rest); ok {
+			return v, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// compareValue сравнивает значение атрибута со строкой из условия
+// с учетом типа: числа, длительности, время, строки
+func compareValue(v slog.Value, s string) (int, bool) {
+	switch v.Kind() {
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64:
+		want, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, false
+		}
+		return cmpFloat(numberOf(v), want), true
+	case slog.KindDuration:
+		want, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, false
+		}
+		return cmpFloat(float64(v.Duration()), float64(want)), true
+	case slog.KindTime:
+		want, err := parseTimeArg(s, time.Now())
+		if err != nil {
+			return 0, false
+		}
+		return v.Time().Compare(want), true
+	case slog.KindString:
+		return strings.Compare(v.String(), s), true
+	}
+	return 0, false
+}
+
+func numberOf(v slog.Value) float64 {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64())
+	case slog.KindUint64:
+		return float64(v.Uint64())
+	default:
+		return v.Float64()
+	}
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// valueString - текстовое представление значения для = и ~
+func valueString(v slog.Value) string {
+	if v.Kind() == slog.KindAny && v.Any() == nil {
+		return "null"
+	}
+	return v.String()
+}
+
+// ──────────────────────────────────────────────────────────
+// Время
+// ──────────────────────────────────────────────────────────
+
+// parseTimeArg разбирает границу -since/-until: длительность назад ("15m"),
+// RFC3339, дату ("2006-01-02"), дату со временем или время сегодня ("15:04")
+func parseTimeArg(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.DateTime, "2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			y, m, d := now.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("не удалось разобрать время %q", s)
+}
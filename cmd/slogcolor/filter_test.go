@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mustCondition разбирает условие -where или завершает тест
+func mustCondition(t *testing.T, s string) condition {
+	t.Helper()
+	c, err := parseCondition(s)
+	if err != nil {
+		t.Fatalf("parseCondition(%q): %v", s, err)
+	}
+	return c
+}
+
+// ──────────────────────────────────────────────────────────
+// Условия
+// ──────────────────────────────────────────────────────────
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		in, key, op, value string
+	}{
+		{"status>=500", "status", ">=", "500"},
+		{"status != 200", "status", "!=", "200"},
+		{"user.name=Bob", "user.name", "=", "Bob"},
+		{"elapsed>1.5s", "elapsed", ">", "1.5s"},
+		{"path~^/api/", "path", "~", "^/api/"},
+		{"q=a=b", "q", "=", "a=b"},
+	}
+	for _, tt := range tests {
+		c := mustCondition(t, tt.in)
+		if c.key != tt.key || c.op != tt.op || c.value != tt.value {
+			t.Errorf("%q: получено %q %q %q", tt.in, c.key, c.op, c.value)
+		}
+	}
+
+	for _, bad := range []string{"status", ">=500", "path~("} {
+		if _, err := parseCondition(bad); err == nil {
+			t.Errorf("%q: ожидалась ошибка", bad)
+		}
+	}
+}
+
+func TestCondition_Match(t *testing.T) {
+	json, err := parseLine([]byte(`{"level":"INFO","msg":"req","status":503,"path":"/api/users","user":{"name":"Bob","id":7},"at":"2026-02-08T12:00:00Z"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logfmt, err := parseLine([]byte(`level=INFO msg=req status=200 elapsed=1.5s http.method=GET`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		cond   string
+		json   bool
+		logfmt bool
+	}{
+		{"status>=500", true, false},
+		{"status<500", false, true},
+		{"status=200", false, true},
+		{"status!=200", true, false},
+		{"path~^/api/", true, false},
+		{"user.name=Bob", true, false},
+		{"user.id>5", true, false},
+		{"http.method=GET", false, true},
+		{"elapsed>1s", false, true},
+		{"elapsed>2s", false, false},
+		{"elapsed>=1500ms", false, true},
+		{"missing=x", false, false},
+		{"missing!=x", true, true},
+		{"status>abc", false, false},
+	}
+	for _, tt := range tests {
+		c := mustCondition(t, tt.cond)
+		if got := c.match(json); got != tt.json {
+			t.Errorf("%q на JSON: %v, ожидалось %v", tt.cond, got, tt.json)
+		}
+		if got := c.match(logfmt); got != tt.logfmt {
+			t.Errorf("%q на logfmt: %v, ожидалось %v", tt.cond, got, tt.logfmt)
+		}
+	}
+}
+
+// mustParse разбирает строку лога или завершает тест
+func mustParse(t *testing.T, line string) slog.Record {
+	t.Helper()
+	r, err := parseLine([]byte(line))
+	if err != nil {
+		t.Fatalf("parseLine(%q): %v", line, err)
+	}
+	return r
+}
+
+// ──────────────────────────────────────────────────────────
+// filter
+// ──────────────────────────────────────────────────────────
+
+func TestFilter_Match(t *testing.T) {
+	f := filter{
+		minLevel:    slog.LevelWarn,
+		hasMinLevel: true,
+		msg:         regexp.MustCompile(`^disk`),
+		since:       time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC),
+		until:       time.Date(2026, 2, 8, 13, 0, 0, 0, time.UTC),
+	}
+
+	tests := map[string]bool{
+		`time=2026-02-08T12:30:00Z level=WARN msg="disk full"`:  true,
+		`time=2026-02-08T12:30:00Z level=INFO msg="disk full"`:  false,
+		`time=2026-02-08T12:30:00Z level=ERROR msg="net down"`:  false,
+		`time=2026-02-08T11:59:59Z level=WARN msg="disk full"`:  false,
+		`time=2026-02-08T13:00:01Z level=WARN msg="disk full"`:  false,
+		`level=WARN msg="disk full"`:                            false,
+		`time=2026-02-08T13:00:00Z level=ERROR msg="disk slow"`: true,
+	}
+	for line, want := range tests {
+		if got := f.match(mustParse(t, line)); got != want {
+			t.Errorf("%s: %v, ожидалось %v", line, got, want)
+		}
+	}
+
+	var empty filter
+	if !empty.match(mustParse(t, `msg=x`)) {
+		t.Error("пустой фильтр должен пропускать все записи")
+	}
+}
+
+func TestParseTimeArg(t *testing.T) {
+	now := time.Date(2026, 2, 8, 12, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"15m":                  now.Add(-15 * time.Minute),
+		"2026-02-07T10:00:00Z": time.Date(2026, 2, 7, 10, 0, 0, 0, time.UTC),
+		"2026-02-07 10:00:05":  time.Date(2026, 2, 7, 10, 0, 5, 0, time.UTC),
+		"2026-02-07":           time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC),
+		"09:15":                time.Date(2026, 2, 8, 9, 15, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := parseTimeArg(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("%q: получено %v (%v), ожидалось %v", in, got, err, want)
+		}
+	}
+	if _, err := parseTimeArg("вчера", now); err == nil {
+		t.Error("ожидалась ошибка для неизвестного формата")
+	}
+}
+
+func TestViewer_FilterHidesContinuation(t *testing.T) {
+	input := strings.Join([]string{
+		`level=ERROR msg=boom`,
+		`goroutine 1 [running]:`,
+		`level=INFO msg=ok`,
+		`	main.go:10`,
+		`level=ERROR msg=again`,
+		`	main.go:20`,
+	}, "\n")
+
+	var out bytes.Buffer
+	bw := bufio.NewWriter(&out)
+	v := newViewer(bw)
+	v.filter = filter{minLevel: slog.LevelError, hasMinLevel: true}
+	if err := v.run(strings.NewReader(input)); err != nil {
+		t.Fatalf("run вернул ошибку: %v", err)
+	}
+	_ = bw.Flush()
+
+	want := "[00:00:00] ERR boom\ngoroutine 1 [running]:\n[00:00:00] ERR again\n\tmain.go:20\n"
+	if out.String() != want {
+		t.Errorf("вывод:\n%q\nожидалось:\n%q", out.String(), want)
+	}
+}
+
+// ──────────────────────────────────────────────────────────
+// followReader
+// ──────────────────────────────────────────────────────────
+
+func TestFollowReader(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(name, []byte("one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fr, err := openFollow(ctx, name, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fr.Close()
+
+	lines := make(chan string)
+	go func() {
+		br := bufio.NewReader(fr)
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Fatalf("прочитано %q, ожидалось %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("не дождались строки %q", want)
+		}
+	}
+	appendTo := func(s string) {
+		t.Helper()
+		f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect("one\n")
+
+	appendTo("two\n")
+	expect("two\n")
+
+	// Усечение: чтение продолжается с начала файла
+	if err := os.WriteFile(name, []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expect("x\n")
+
+	// Ротация: файл переименован, на его месте новый
+	if err := os.Rename(name, name+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte("rotated\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expect("rotated\n")
+
+	cancel()
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Fatal("после отмены контекста не должно быть строк")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read не вернул io.EOF после отмены контекста")
+	}
+}
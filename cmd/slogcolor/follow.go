@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// followReader читает файл как tail -f: на конце файла ждет новых данных,
+// начинает сначала при усечении и переоткрывает файл после ротации
+type followReader struct {
+	ctx  context.Context
+	name string
+	poll time.Duration
+
+	f   *os.File
+	off int64
+}
+
+// openFollow открывает name для чтения в режиме слежения
+func openFollow(ctx context.Context, name string, poll time.Duration) (*followReader, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &followReader{ctx: ctx, name: name, poll: poll, f: f}, nil
+}
+
+// Read возвращает io.EOF только после отмены контекста
+func (fr *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := fr.f.Read(p)
+		fr.off += int64(n)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		if err := fr.checkFile(); err != nil {
+			return 0, err
+		}
+
+		select {
+		case <-fr.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(fr.poll):
+		}
+	}
+}
+
+// checkFile обрабатывает усечение и замену файла
+func (fr *followReader) checkFile() error {
+	cur, err := fr.f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Файл по тому же пути заменен (ротация): переходим на новый
+	if info, err := os.Stat(fr.name); err == nil && !os.SameFile(cur, info) {
+		f, err := os.Open(fr.name)
+		if err != nil {
+			return nil // новый файл еще не создан до конца, попробуем позже
+		}
+		fr.f.Close()
+		fr.f, fr.off = f, 0
+		return nil
+	}
+
+	// Файл усечен (copytruncate): читаем с начала
+	if cur.Size() < fr.off {
+		if _, err := fr.f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		fr.off = 0
+	}
+	return nil
+}
+
+// Close закрывает текущий файл
+func (fr *followReader) Close() error {
+	return fr.f.Close()
+}
@@ -0,0 +1,224 @@
+// Команда slogcolor раскрашивает логи slog.JSONHandler и slog.TextHandler
+// (logfmt), читая их из файлов или stdin и выводя через logger.ColorHandler.
+// Строки, которые не удалось разобрать, выводятся без изменений.
+//
+// Записи можно отфильтровать по уровню, сообщению, атрибутам и времени,
+// а с флагом -f следить за дописываемым файлом, как tail -f. Флаг -tui
+// открывает интерактивный просмотр с прокруткой, поиском и панелью атрибутов.
+//
+//	kubectl logs deploy/api | slogcolor
+//	slogcolor -color=always app.log | less -R
+//	slogcolor -level=warn -where 'status>=500' -since=15m -f app.log
+//	slogcolor -tui app.log
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+
+	logger "github.com/golub15/slog_color"
+)
+
+func main() {
+	colorMode := flag.String("color", "auto", "цвета: auto, always или never")
+	level := flag.String("level", "", "минимальный уровень: debug, info, warn, error")
+	grep := flag.String("grep", "", "регулярное выражение для сообщения")
+	since := flag.String("since", "", "записи не раньше: RFC3339, дата, время или длительность назад (15m)")
+	until := flag.String("until", "", "записи не позже, формат как у -since")
+	follow := flag.Bool("f", false, "следить за дописываемым файлом, как tail -f")
+	tui := flag.Bool("tui", false, "интерактивный просмотр файла (Linux)")
+	var conds []condition
+	flag.Func("where", "условие на атрибут: key=v, key!=v, key>=500, key<1s, key~regexp (можно повторять)", func(s string) error {
+		c, err := parseCondition(s)
+		if err != nil {
+			return err
+		}
+		conds = append(conds, c)
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Использование: slogcolor [флаги] [файл ...]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch *colorMode {
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	case "auto":
+		// Интерактивный режим рисует в /dev/tty, даже если stdout перенаправлен
+		if *tui {
+			color.NoColor = false
+		}
+	default:
+		fatalf("неизвестное значение -color: %q", *colorMode)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	v := newViewer(out)
+
+	v.filter.conds = conds
+	if *level != "" {
+		lvl, ok := parseLevel(*level)
+		if !ok {
+			fatalf("неизвестный уровень -level: %q", *level)
+		}
+		v.filter.minLevel, v.filter.hasMinLevel = lvl, true
+	}
+	if *grep != "" {
+		re, err := regexp.Compile(*grep)
+		if err != nil {
+			fatalf("-grep: %v", err)
+		}
+		v.filter.msg = re
+	}
+	now := time.Now()
+	for _, b := range []struct {
+		arg string
+		dst *time.Time
+	}{{*since, &v.filter.since}, {*until, &v.filter.until}} {
+		if b.arg == "" {
+			continue
+		}
+		t, err := parseTimeArg(b.arg, now)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		*b.dst = t
+	}
+
+	switch {
+	case *tui:
+		if flag.NArg() != 1 {
+			fatalf("флаг -tui требует ровно один файл")
+		}
+		if err := runTUI(flag.Arg(0), v.filter, *follow); err != nil {
+			fatalf("%v", err)
+		}
+	case *follow:
+		if flag.NArg() != 1 {
+			fatalf("флаг -f требует ровно один файл")
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := v.followFile(ctx, flag.Arg(0)); err != nil {
+			fatalf("%v", err)
+		}
+	case flag.NArg() == 0:
+		if err := v.run(os.Stdin); err != nil {
+			fatalf("%v", err)
+		}
+	default:
+		for _, name := range flag.Args() {
+			if err := v.runFile(name); err != nil {
+				fatalf("%v", err)
+			}
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "slogcolor: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// viewer разбирает строки и выводит их через ColorHandler
+type viewer struct {
+	out     *bufio.Writer
+	handler *logger.ColorHandler
+	filter  filter
+
+	// hide - последняя запись отброшена фильтром; следующие за ней
+	// неразобранные строки (стектрейсы) тоже не выводятся
+	hide bool
+}
+
+func newViewer(out *bufio.Writer) *viewer {
+	return &viewer{out: out, handler: logger.NewColorHandler(out)}
+}
+
+// runFile выводит содержимое файла name
+func (v *viewer) runFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return v.run(f)
+}
+
+// followPoll - период опроса файла в режиме -f
+const followPoll = 250 * time.Millisecond
+
+// followFile выводит файл name и затем дописываемые строки до отмены ctx
+func (v *viewer) followFile(ctx context.Context, name string) error {
+	fr, err := openFollow(ctx, name, followPoll)
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+	return v.run(fr)
+}
+
+// run читает строки из r до конца ввода
+func (v *viewer) run(r io.Reader) error {
+	br := bufio.NewReaderSize(r, 64<<10)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if werr := v.line(line); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Данных больше нет - сбрасываем вывод, чтобы pipe не ждал
+		if br.Buffered() == 0 {
+			if err := v.out.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// line выводит одну строку: запись через ColorHandler или как есть
+func (v *viewer) line(line []byte) error {
+	r, err := parseLine(line)
+	if err != nil {
+		if v.hide {
+			return nil
+		}
+		if _, err := v.out.Write(line); err != nil {
+			return err
+		}
+		if line[len(line)-1] != '\n' {
+			return v.out.WriteByte('\n')
+		}
+		return nil
+	}
+	if v.hide = !v.filter.match(r); v.hide {
+		return nil
+	}
+	return v.handler.Handle(context.Background(), r)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errNotRecord - строка не похожа на запись slog
+var errNotRecord = errors.New("not a log record")
+
+// parseLine разбирает строку JSON (slog.JSONHandler) или logfmt
+// (slog.TextHandler) в slog.Record
+func parseLine(line []byte) (slog.Record, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return slog.Record{}, errNotRecord
+	}
+	if line[0] == '{' {
+		return parseJSON(line)
+	}
+	return parseLogfmt(line)
+}
+
+// recordBuilder собирает запись из пар ключ-значение, выделяя
+// служебные ключи time, level и msg
+type recordBuilder struct {
+	time     time.Time
+	level    slog.Level
+	msg      string
+	hasLevel bool
+	hasMsg   bool
+	attrs    []slog.Attr
+}
+
+// add обрабатывает одну пару верхнего уровня
+func (b *recordBuilder) add(key string, value slog.Value) {
+	switch key {
+	case slog.TimeKey:
+		if t, ok := parseTime(value); ok {
+			b.time = t
+			return
+		}
+	case slog.LevelKey:
+		if value.Kind() == slog.KindString {
+			if lvl, ok := parseLevel(value.String()); ok {
+				b.level, b.hasLevel = lvl, true
+				return
+			}
+		}
+	case slog.MessageKey:
+		if value.Kind() == slog.KindString {
+			b.msg, b.hasMsg = value.String(), true
+			return
+		}
+	}
+	b.attrs = append(b.attrs, slog.Attr{Key: key, Value: value})
+}
+
+// record возвращает собранную запись; строка без level и msg записью не считается
+func (b *recordBuilder) record() (slog.Record, error) {
+	if !b.hasLevel && !b.hasMsg {
+		return slog.Record{}, errNotRecord
+	}
+	if !b.hasLevel {
+		b.level = slog.LevelInfo
+	}
+	r := slog.NewRecord(b.time, b.level, b.msg, 0)
+	r.AddAttrs(b.attrs...)
+	return r, nil
+}
+
+// ──────────────────────────────────────────────────────────
+// JSON
+// ──────────────────────────────────────────────────────────
+
+// parseJSON разбирает объект JSON, сохраняя порядок ключей
+func parseJSON(line []byte) (slog.Record, error) {
+	var b recordBuilder
+	err := walkJSONObject(line, func(key string, value slog.Value) {
+		b.add(key, value)
+	})
+	if err != nil {
+		return slog.Record{}, err
+	}
+	return b.record()
+}
+
+// walkJSONObject вызывает fn для каждого поля объекта в порядке следования
+func walkJSONObject(data []byte, fn func(key string, value slog.Value)) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return errNotRecord
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		value, err := jsonValue(raw)
+		if err != nil {
+			return err
+		}
+		fn(key, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	// После объекта в строке ничего не должно быть
+	if _, err := dec.Token(); err == nil {
+		return errNotRecord
+	}
+	return nil
+}
+
+// jsonValue превращает значение JSON в slog.Value: объекты - в группы,
+// массивы - в компактную строку JSON
+func jsonValue(raw json.RawMessage) (slog.Value, error) {
+	switch raw[0] {
+	case '{':
+		var attrs []slog.Attr
+		err := walkJSONObject(raw, func(key string, value slog.Value) {
+			attrs = append(attrs, slog.Attr{Key: key, Value: value})
+		})
+		return slog.GroupValue(attrs...), err
+	case '[':
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return slog.Value{}, err
+		}
+		return slog.StringValue(compact.String()), nil
+	case '"':
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return slog.StringValue(s), err
+	case 't', 'f':
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return slog.BoolValue(v), err
+	case 'n':
+		return slog.AnyValue(nil), nil
+	default:
+		return numberValue(string(raw)), nil
+	}
+}
+
+// ──────────────────────────────────────────────────────────
+// logfmt
+// ──────────────────────────────────────────────────────────
+
+// parseLogfmt разбирает строку key=value key="quoted value" ...
+func parseLogfmt(line []byte) (slog.Record, error) {
+	var b recordBuilder
+	s := string(line)
+
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			break
+		}
+
+		eq := strings.IndexAny(s, "= ")
+		if eq <= 0 || s[eq] != '=' {
+			return slog.Record{}, errNotRecord
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var raw string
+		quoted := strings.HasPrefix(s, `"`)
+		if quoted {
+			end := quotedEnd(s)
+			if end < 0 {
+				return slog.Record{}, errNotRecord
+			}
+			unq, err := strconv.Unquote(s[:end])
+			if err != nil {
+				return slog.Record{}, errNotRecord
+			}
+			raw, s = unq, s[end:]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			raw, s = s[:end], s[end:]
+		}
+
+		if quoted {
+			b.add(key, slog.StringValue(raw))
+		} else {
+			b.add(key, logfmtValue(raw))
+		}
+	}
+	return b.record()
+}
+
+// quotedEnd возвращает индекс за закрывающей кавычкой строки s,
+// начинающейся с '"', или -1
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// logfmtValue угадывает тип значения без кавычек
+func logfmtValue(raw string) slog.Value {
+	switch raw {
+	case "true":
+		return slog.BoolValue(true)
+	case "false":
+		return slog.BoolValue(false)
+	}
+	if d, err := time.ParseDuration(raw); err == nil && strings.ContainsAny(raw, "hmsuµn") {
+		return slog.DurationValue(d)
+	}
+	if v := numberValue(raw); v.Kind() != slog.KindString {
+		return v
+	}
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return slog.TimeValue(t)
+	}
+	return slog.StringValue(raw)
+}
+
+// ──────────────────────────────────────────────────────────
+// Общие значения
+// ──────────────────────────────────────────────────────────
+
+// numberValue разбирает целое или вещественное число; иначе строка
+func numberValue(raw string) slog.Value {
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return slog.Int64Value(i)
+	}
+	if u, err := strconv.ParseUint(raw, 10, 64); err == nil {
+		return slog.Uint64Value(u)
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return slog.Float64Value(f)
+	}
+	return slog.StringValue(raw)
+}
+
+// parseTime принимает время строкой RFC3339 (JSON) или уже разобранное (logfmt)
+func parseTime(v slog.Value) (time.Time, bool) {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time(), true
+	case slog.KindString:
+		t, err := time.Parse(time.RFC3339Nano, v.String())
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// parseLevel разбирает уровень slog ("INFO", "WARN+2") и распространенные синонимы
+func parseLevel(s string) (slog.Level, bool) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(s)); err == nil {
+		return lvl, true
+	}
+	switch strings.ToUpper(s) {
+	case "DBG", "TRACE":
+		return slog.LevelDebug, true
+	case "INF":
+		return slog.LevelInfo, true
+	case "WRN", "WARNING":
+		return slog.LevelWarn, true
+	case "ERR", "FATAL", "PANIC", "CRITICAL":
+		return slog.LevelError, true
+	}
+	return 0, false
+}
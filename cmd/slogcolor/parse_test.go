@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func init() {
+	color.NoColor = true
+}
+
+// attrsOf возвращает атрибуты записи в виде key=value через пробел
+func attrsOf(r slog.Record) string {
+	var parts []string
+	r.Attrs(func(a slog.Attr) bool {
+		parts = append(parts, a.String())
+		return true
+	})
+	return strings.Join(parts, " ")
+}
+
+// ──────────────────────────────────────────────────────────
+// parseLine
+// ──────────────────────────────────────────────────────────
+
+func TestParseLine_JSON(t *testing.T) {
+	line := `{"time":"2026-02-08T12:30:45.123Z","level":"WARN","msg":"slow","ms":1200,"ratio":0.5,"ok":true,"user":{"id":7,"name":"Bob"},"tags":["a", "b"],"none":null}`
+
+	r, err := parseLine([]byte(line))
+	if err != nil {
+		t.Fatalf("parseLine вернул ошибку: %v", err)
+	}
+
+	if want := time.Date(2026, 2, 8, 12, 30, 45, 123e6, time.UTC); !r.Time.Equal(want) {
+		t.Errorf("time = %v, ожидалось %v", r.Time, want)
+	}
+	if r.Level != slog.LevelWarn || r.Message != "slow" {
+		t.Errorf("level=%v msg=%q", r.Level, r.Message)
+	}
+
+	want := `ms=1200 ratio=0.5 ok=true user=[id=7 name=Bob] tags=["a","b"] none=<nil>`
+	if got := attrsOf(r); got != want {
+		t.Errorf("атрибуты = %s\nожидалось  %s", got, want)
+	}
+}
+
+func TestParseLine_Logfmt(t *testing.T) {
+	line := `time=2026-02-08T12:30:45.000Z level=ERROR msg="query failed" table=orders ms=12 elapsed=1.5s err="deadlock \"detected\"" http.status=500`
+
+	r, err := parseLine([]byte(line))
+	if err != nil {
+		t.Fatalf("parseLine вернул ошибку: %v", err)
+	}
+	if r.Level != slog.LevelError || r.Message != "query failed" {
+		t.Errorf("level=%v msg=%q", r.Level, r.Message)
+	}
+
+	want := `table=orders ms=12 elapsed=1.5s err=deadlock "detected" http.status=500`
+	if got := attrsOf(r); got != want {
+		t.Errorf("атрибуты = %s\nожидалось  %s", got, want)
+	}
+
+	var kinds []slog.Kind
+	r.Attrs(func(a slog.Attr) bool {
+		kinds = append(kinds, a.Value.Kind())
+		return true
+	})
+	if kinds[1] != slog.KindInt64 || kinds[2] != slog.KindDuration {
+		t.Errorf("типы значений logfmt не распознаны: %v", kinds)
+	}
+}
+
+func TestParseLine_Levels(t *testing.T) {
+	tests := map[string]slog.Level{
+		"DEBUG":   slog.LevelDebug,
+		"info":    slog.LevelInfo,
+		"WARN+2":  slog.LevelWarn + 2,
+		"warning": slog.LevelWarn,
+		"ERR":     slog.LevelError,
+	}
+	for in, want := range tests {
+		r, err := parseLine([]byte(`level=` + in + ` msg=x`))
+		if err != nil || r.Level != want {
+			t.Errorf("уровень %q: получено %v (%v), ожидалось %v", in, r.Level, err, want)
+		}
+	}
+}
+
+func TestParseLine_NotRecord(t *testing.T) {
+	lines := []string{
+		"",
+		"panic: runtime error",
+		"goroutine 1 [running]:",
+		`{"broken": `,
+		`{"a":1} trailing`,
+		`{"only":"fields"}`,
+		`key="unterminated`,
+	}
+	for _, line := range lines {
+		if _, err := parseLine([]byte(line)); err == nil {
+			t.Errorf("строка %q ошибочно разобрана как запись", line)
+		}
+	}
+}
+
+// ──────────────────────────────────────────────────────────
+// viewer
+// ──────────────────────────────────────────────────────────
+
+func TestViewer_Run(t *testing.T) {
+	input := strings.Join([]string{
+		`{"time":"2026-02-08T12:30:45Z","level":"INFO","msg":"started","port":8080}`,
+		`not a log line`,
+		`time=2026-02-08T12:30:46.000Z level=WARN msg="disk usage" percent=91.4`,
+		`no trailing newline`,
+	}, "\n")
+
+	var out bytes.Buffer
+	bw := bufio.NewWriter(&out)
+	if err := newViewer(bw).run(strings.NewReader(input)); err != nil {
+		t.Fatalf("run вернул ошибку: %v", err)
+	}
+	_ = bw.Flush()
+
+	want := strings.Join([]string{
+		`[12:30:45] INF started port=8080`,
+		`not a log line`,
+		`[12:30:46] WRN disk usage percent=91.4`,
+		`no trailing newline`,
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Errorf("вывод:\n%s\nожидалось:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,55 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// resizeSignals - сигналы об изменении размера терминала
+var resizeSignals = []os.Signal{unix.SIGWINCH}
+
+// terminal переводит tty в raw-режим и восстанавливает прежние настройки
+type terminal struct {
+	fd  int
+	old unix.Termios
+}
+
+// openTerminal включает raw-режим: без эха, построчной буферизации,
+// обработки Ctrl-C и преобразования \n на выводе
+func openTerminal(tty *os.File) (*terminal, error) {
+	fd := int(tty.Fd())
+	old, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *old
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return &terminal{fd: fd, old: *old}, nil
+}
+
+// size возвращает ширину и высоту терминала в символах
+func (t *terminal) size() (width, height int, err error) {
+	ws, err := unix.IoctlGetWinsize(t.fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// restore возвращает исходные настройки терминала
+func (t *terminal) restore() error {
+	return unix.IoctlSetTermios(t.fd, unix.TCSETS, &t.old)
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// resizeSignals - сигналы об изменении размера терминала
+var resizeSignals []os.Signal
+
+// terminal - заглушка: интерактивный режим поддерживается только в Linux
+type terminal struct{}
+
+func openTerminal(*os.File) (*terminal, error) {
+	return nil, errors.New("режим -tui поддерживается только в Linux")
+}
+
+func (t *terminal) size() (width, height int, err error) { return 0, 0, nil }
+
+func (t *terminal) restore() error { return nil }
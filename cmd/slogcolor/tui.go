@@ -0,0 +1,845 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	logger "github.com/golub15/slog_color"
+)
+
+// ──────────────────────────────────────────────────────────
+// Записи браузера
+// ──────────────────────────────────────────────────────────
+
+// Корзины уровней для переключателей d/i/w/e
+const (
+	bucketDebug = iota
+	bucketInfo
+	bucketWarn
+	bucketError
+	bucketCount
+
+	bucketNone = -1 // строка до первой записи: видна всегда
+)
+
+var bucketLabels = [bucketCount]string{"D", "I", "W", "E"}
+
+// entry - строка лога в браузере
+type entry struct {
+	line   string      // исходная строка без перевода строки
+	rec    slog.Record // разобранная запись, если isRec
+	isRec  bool
+	text   []rune // строка списка: вывод ColorHandler с ANSI
+	plain  []rune // text без ANSI, по нему идет поиск
+	bucket int    // корзина уровня; у неразобранных строк - как у предыдущей записи
+}
+
+// bucketOf относит уровень к одной из корзин
+func bucketOf(lvl slog.Level) int {
+	switch {
+	case lvl < slog.LevelInfo:
+		return bucketDebug
+	case lvl < slog.LevelWarn:
+		return bucketInfo
+	case lvl < slog.LevelError:
+		return bucketWarn
+	}
+	return bucketError
+}
+
+// renderer превращает строки лога в записи браузера через ColorHandler
+type renderer struct {
+	buf     bytes.Buffer
+	handler *logger.ColorHandler
+	filter  filter
+	hide    bool // см. viewer.hide
+	bucket  int
+}
+
+func newRenderer(f filter) *renderer {
+	rd := &renderer{filter: f, bucket: bucketNone}
+	rd.handler = logger.NewColorHandler(&rd.buf)
+	return rd
+}
+
+// entry разбирает строку; false - строка отброшена фильтром
+func (rd *renderer) entry(line []byte) (entry, bool) {
+	line = bytes.TrimRight(line, "\r\n")
+	e := entry{line: string(line)}
+
+	r, err := parseLine(line)
+	if err != nil {
+		if rd.hide {
+			return e, false
+		}
+		e.text = sanitize(stripEscapes(e.line))
+	} else {
+		if rd.hide = !rd.filter.match(r); rd.hide {
+			return e, false
+		}
+		// В выводе ColorHandler остаются только его собственные SGR
+		r = cleanRecord(r)
+		rd.buf.Reset()
+		_ = rd.handler.Handle(context.Background(), r)
+		e.rec, e.isRec = r, true
+		e.text = sanitize(strings.TrimSuffix(rd.buf.String(), "\n"))
+		rd.bucket = bucketOf(r.Level)
+	}
+	e.bucket = rd.bucket
+	e.plain = stripANSI(e.text)
+	return e, true
+}
+
+// stripEscapes удаляет из текста лога escape-последовательности (CSI, OSC
+// и прочие) тем же разбором, что и StripWriter. Иначе содержимое лога
+// управляло бы терминалом: буфером обмена (OSC 52), заголовком окна, курсором.
+func stripEscapes(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	_, _ = logger.NewStripWriter(&b).Write([]byte(s))
+	return b.String()
+}
+
+// cleanRecord возвращает запись, в которой сообщение, ключи и строковые
+// значения очищены stripEscapes
+func cleanRecord(r slog.Record) slog.Record {
+	clean := slog.NewRecord(r.Time, r.Level, stripEscapes(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(cleanAttr(a))
+		return true
+	})
+	return clean
+}
+
+func cleanAttr(a slog.Attr) slog.Attr {
+	a.Key = stripEscapes(a.Key)
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(stripEscapes(a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = cleanAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	}
+	return a
+}
+
+// sanitize убирает из строки управляющие символы, ломающие разметку экрана.
+// Из escape-последовательностей остаются только SGR (цвета ColorHandler'а),
+// остальные ESC заменяются пробелом.
+func sanitize(s string) []rune {
+	out := make([]rune, 0, len(s))
+	for i, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ', ' ', ' ', ' ')
+		case r == '\x1b' && isSGR(s[i:]):
+			out = append(out, r)
+		case unicode.IsControl(r):
+			out = append(out, ' ')
+		default:
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// isSGR сообщает, начинается ли s с SGR: ESC [ цифры и ';' m
+func isSGR(s string) bool {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return false
+	}
+	for i := 2; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == 'm':
+			return true
+		case c >= '0' && c <= '9' || c == ';':
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// escapeLen возвращает длину ANSI-последовательности в начале s или 0.
+// После sanitize в строке остаются только SGR.
+func escapeLen(s []rune) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	if s[1] != '[' {
+		return 2
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// stripANSI возвращает видимые символы строки
+func stripANSI(s []rune) []rune {
+	out := make([]rune, 0, len(s))
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		out = append(out, s[i])
+		i++
+	}
+	return out
+}
+
+// ──────────────────────────────────────────────────────────
+// Поиск
+// ──────────────────────────────────────────────────────────
+
+// span - диапазон видимых символов [start, end)
+type span struct{ start, end int }
+
+// findAll возвращает вхождения query в s без учета регистра
+func findAll(s, query []rune) []span {
+	if len(query) == 0 {
+		return nil
+	}
+	var spans []span
+	for i := 0; i+len(query) <= len(s); i++ {
+		if equalFold(s[i:i+len(query)], query) {
+			spans = append(spans, span{i, i + len(query)})
+			i += len(query) - 1
+		}
+	}
+	return spans
+}
+
+func equalFold(a, b []rune) bool {
+	for i := range a {
+		if unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Инверсия для подсветки; \x1b[27m выключает только ее, не трогая цвета
+const (
+	ansiInverse    = "\x1b[7m"
+	ansiInverseOff = "\x1b[27m"
+	ansiDim        = "\x1b[2m"
+	ansiReset      = "\x1b[0m"
+)
+
+// appendCell дописывает строку s с ANSI, обрезанную до width видимых
+// символов, с подсветкой диапазонов hl, и возвращает число видимых символов
+func appendCell(buf []byte, s []rune, width int, hl []span) ([]byte, int) {
+	col := 0
+	inHL := false
+	for i := 0; i < len(s) && col < width; {
+		if n := escapeLen(s[i:]); n > 0 {
+			buf = append(buf, string(s[i:i+n])...)
+			if inHL {
+				// Сброс цвета в записи гасит и инверсию
+				buf = append(buf, ansiInverse...)
+			}
+			i += n
+			continue
+		}
+		for len(hl) > 0 && hl[0].end <= col {
+			hl = hl[1:]
+		}
+		if want := len(hl) > 0 && hl[0].start <= col; want != inHL {
+			inHL = want
+			if inHL {
+				buf = append(buf, ansiInverse...)
+			} else {
+				buf = append(buf, ansiInverseOff...)
+			}
+		}
+		buf = utf8.AppendRune(buf, s[i])
+		col++
+		i++
+	}
+	return append(buf, ansiReset...), col
+}
+
+// ──────────────────────────────────────────────────────────
+// Модель браузера
+// ──────────────────────────────────────────────────────────
+
+// browser - состояние интерактивного просмотра. Все методы вызываются
+// из одной горутины цикла событий.
+type browser struct {
+	name    string
+	entries []entry
+	visible []int // индексы entries, проходящие переключатели уровней
+	hidden  [bucketCount]bool
+
+	cursor int // позиция в visible
+	top    int // первая строка экрана в visible
+	width  int
+	height int
+
+	follow    bool // курсор следует за новыми записями
+	detail    bool // боковая панель с атрибутами текущей записи
+	detailTop int
+
+	query     []rune
+	input     bool // строка поиска редактируется
+	prevQuery []rune
+	origin    int // позиция курсора в начале ввода
+}
+
+func newBrowser(name string) *browser {
+	return &browser{name: name, width: 80, height: 24}
+}
+
+// add дописывает записи в конец
+func (b *browser) add(entries ...entry) {
+	for _, e := range entries {
+		b.entries = append(b.entries, e)
+		if b.shown(e) {
+			b.visible = append(b.visible, len(b.entries)-1)
+		}
+	}
+	if b.follow {
+		b.setCursor(len(b.visible) - 1)
+	}
+}
+
+func (b *browser) shown(e entry) bool {
+	return e.bucket == bucketNone || !b.hidden[e.bucket]
+}
+
+// toggle переключает видимость корзины уровня, сохраняя текущую запись
+func (b *browser) toggle(bucket int) {
+	cur := -1
+	if b.cursor < len(b.visible) {
+		cur = b.visible[b.cursor]
+	}
+	b.hidden[bucket] = !b.hidden[bucket]
+
+	b.visible = b.visible[:0]
+	pos := -1
+	for i, e := range b.entries {
+		if !b.shown(e) {
+			continue
+		}
+		if pos < 0 && i >= cur {
+			pos = len(b.visible)
+		}
+		b.visible = append(b.visible, i)
+	}
+	if pos < 0 {
+		pos = len(b.visible) - 1
+	}
+	b.setCursor(pos)
+}
+
+// listHeight - число строк списка (последняя строка экрана - статус)
+func (b *browser) listHeight() int {
+	return max(b.height-1, 1)
+}
+
+// setCursor ставит курсор в пределах списка и прокручивает экран к нему
+func (b *browser) setCursor(pos int) {
+	pos = min(pos, len(b.visible)-1)
+	pos = max(pos, 0)
+	if pos != b.cursor {
+		b.detailTop = 0
+	}
+	b.cursor = pos
+
+	lh := b.listHeight()
+	if b.cursor < b.top {
+		b.top = b.cursor
+	}
+	if b.cursor >= b.top+lh {
+		b.top = b.cursor - lh + 1
+	}
+	b.top = max(min(b.top, len(b.visible)-lh), 0)
+}
+
+// move сдвигает курсор; движение вверх выключает слежение
+func (b *browser) move(n int) {
+	if n < 0 {
+		b.follow = false
+	}
+	b.setCursor(b.cursor + n)
+}
+
+// current возвращает запись под курсором
+func (b *browser) current() (entry, bool) {
+	if b.cursor >= len(b.visible) {
+		return entry{}, false
+	}
+	return b.entries[b.visible[b.cursor]], true
+}
+
+// search ищет следующее (dir > 0) или предыдущее вхождение query,
+// начиная с позиции from включительно
+func (b *browser) search(from, dir int) bool {
+	if len(b.query) == 0 || len(b.visible) == 0 {
+		return false
+	}
+	n := len(b.visible)
+	for k := range n {
+		pos := ((from+dir*k)%n + n) % n
+		if findAll(b.entries[b.visible[pos]].plain, b.query) != nil {
+			b.follow = false
+			b.setCursor(pos)
+			return true
+		}
+	}
+	return false
+}
+
+// ──────────────────────────────────────────────────────────
+// Клавиши
+// ──────────────────────────────────────────────────────────
+
+// key - нажатая клавиша: имя спецклавиши или символ
+type key string
+
+const (
+	keyUp        key = "up"
+	keyDown      key = "down"
+	keyPgUp      key = "pgup"
+	keyPgDn      key = "pgdn"
+	keyHome      key = "home"
+	keyEnd       key = "end"
+	keyEnter     key = "enter"
+	keyTab       key = "tab"
+	keyEsc       key = "esc"
+	keyBackspace key = "backspace"
+	keyCtrlC     key = "ctrl-c"
+)
+
+// escapeKeys - последовательности, которые посылают терминалы Linux
+var escapeKeys = map[string]key{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPgUp,
+	"\x1b[6~": keyPgDn,
+	"\x1b[H":  keyHome,
+	"\x1bOH":  keyHome,
+	"\x1b[1~": keyHome,
+	"\x1b[7~": keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1bOF":  keyEnd,
+	"\x1b[4~": keyEnd,
+	"\x1b[8~": keyEnd,
+}
+
+// parseKeys разбирает прочитанные из терминала байты в клавиши
+func parseKeys(data []byte) []key {
+	var keys []key
+	for len(data) > 0 {
+		switch c := data[0]; c {
+		case '\x1b':
+			n := escapeSeqLen(data)
+			if n == 1 {
+				keys = append(keys, keyEsc)
+			} else if k, ok := escapeKeys[string(data[:n])]; ok {
+				keys = append(keys, k)
+			}
+			// Неизвестные последовательности пропускаются
+			data = data[n:]
+			continue
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case '\t':
+			keys = append(keys, keyTab)
+		case 0x7f, 0x08:
+			keys = append(keys, keyBackspace)
+		case 0x03:
+			keys = append(keys, keyCtrlC)
+		default:
+			r, size := utf8.DecodeRune(data)
+			if unicode.IsPrint(r) {
+				keys = append(keys, key(string(r)))
+			}
+			data = data[size:]
+			continue
+		}
+		data = data[1:]
+	}
+	return keys
+}
+
+// escapeSeqLen возвращает длину последовательности, начинающейся с ESC;
+// 1 - одиночный Esc
+func escapeSeqLen(data []byte) int {
+	if len(data) < 2 {
+		return 1
+	}
+	switch data[1] {
+	case 'O':
+		return min(3, len(data))
+	case '[':
+		for i := 2; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(data)
+	}
+	return 1
+}
+
+// handleKey применяет клавишу к состоянию; true - выход
+func (b *browser) handleKey(k key) bool {
+	if b.input {
+		b.handleInput(k)
+		return false
+	}
+
+	page := b.listHeight() - 1
+	switch k {
+	case "q", keyCtrlC:
+		return true
+	case "j", keyDown:
+		b.move(1)
+	case "k", keyUp:
+		b.move(-1)
+	case " ", keyPgDn:
+		b.move(page)
+	case "b", keyPgUp:
+		b.move(-page)
+	case "g", keyHome:
+		b.move(-len(b.visible))
+	case "G", keyEnd:
+		b.move(len(b.visible))
+	case "f":
+		b.follow = !b.follow
+		if b.follow {
+			b.setCursor(len(b.visible) - 1)
+		}
+	case "d", "i", "w", "e":
+		b.toggle(strings.Index("diwe", string(k)))
+	case "/":
+		b.input, b.prevQuery, b.origin = true, b.query, b.cursor
+		b.query = nil
+	case "n":
+		b.search(b.cursor+1, 1)
+	case "N":
+		b.search(b.cursor-1, -1)
+	case keyEsc:
+		b.query = nil
+	case keyEnter, keyTab:
+		b.detail = !b.detail
+		b.detailTop = 0
+	case "J":
+		b.detailTop++
+	case "K":
+		b.detailTop = max(b.detailTop-1, 0)
+	}
+	return false
+}
+
+// handleInput редактирует строку поиска; поиск идет по мере ввода
+func (b *browser) handleInput(k key) {
+	switch k {
+	case keyEnter:
+		b.input = false
+		return
+	case keyEsc, keyCtrlC:
+		b.input, b.query = false, b.prevQuery
+		b.setCursor(b.origin)
+		return
+	case keyBackspace:
+		if len(b.query) > 0 {
+			b.query = b.query[:len(b.query)-1]
+		}
+	default:
+		r, size := utf8.DecodeRuneInString(string(k))
+		if size != len(k) {
+			return // спецклавиша
+		}
+		b.query = append(b.query, r)
+	}
+	if !b.search(b.origin, 1) {
+		b.setCursor(b.origin)
+	}
+}
+
+// ──────────────────────────────────────────────────────────
+// Отрисовка
+// ──────────────────────────────────────────────────────────
+
+// draw отрисовывает экран целиком
+func (b *browser) draw(w *bufio.Writer) error {
+	var buf []byte
+	buf = append(buf, "\x1b[H"...)
+
+	listW, paneW := b.width, 0
+	var pane [][]rune
+	if cur, ok := b.current(); b.detail && ok && b.width >= 40 {
+		listW = b.width / 2
+		paneW = b.width - listW - 1
+		pane = wrapLines(details(cur), paneW)
+		b.detailTop = max(min(b.detailTop, len(pane)-b.listHeight()), 0)
+		pane = pane[b.detailTop:]
+	}
+
+	lh := b.listHeight()
+	for row := range lh {
+		n := 0
+		if pos := b.top + row; pos < len(b.visible) {
+			e := b.entries[b.visible[pos]]
+			marker := "  "
+			if pos == b.cursor {
+				marker = "▸ "
+			}
+			buf = append(buf, marker...)
+			buf, n = appendCell(buf, e.text, listW-2, findAll(e.plain, b.query))
+			n += 2
+		}
+		if paneW > 0 {
+			buf = appendSpaces(buf, listW-n)
+			buf = append(buf, ansiDim+"│"+ansiReset...)
+			if row < len(pane) {
+				buf, _ = appendCell(buf, pane[row], paneW, findAll(pane[row], b.query))
+			}
+		}
+		buf = append(buf, "\x1b[K\r\n"...)
+	}
+
+	status := []rune(b.status())
+	buf = append(buf, ansiInverse...)
+	buf, n := appendCell(buf, status, b.width, nil)
+	buf = append(buf, ansiInverse...)
+	buf = appendSpaces(buf, b.width-n)
+	buf = append(buf, ansiReset...)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// status - строка состояния внизу экрана
+func (b *browser) status() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, " %s  %d/%d  ", b.name, min(b.cursor+1, len(b.visible)), len(b.visible))
+	for i, label := range bucketLabels {
+		if b.hidden[i] {
+			label = strings.ToLower(label)
+		}
+		sb.WriteString(label)
+	}
+	if b.follow {
+		sb.WriteString("  FOLLOW")
+	}
+	switch {
+	case b.input:
+		fmt.Fprintf(&sb, "  /%s_", string(b.query))
+	case len(b.query) > 0:
+		fmt.Fprintf(&sb, "  /%s (n/N)", string(b.query))
+	default:
+		sb.WriteString("  q выход  / поиск  d/i/w/e уровни  f слежение  Enter подробно")
+	}
+	return sb.String()
+}
+
+func appendSpaces(buf []byte, n int) []byte {
+	for range n {
+		buf = append(buf, ' ')
+	}
+	return buf
+}
+
+// details возвращает строки боковой панели: поля записи и атрибуты,
+// значения JSON - с отступами
+func details(e entry) []string {
+	if !e.isRec {
+		return []string{stripEscapes(e.line)}
+	}
+	r := e.rec
+	var lines []string
+	if !r.Time.IsZero() {
+		lines = append(lines, "time  "+r.Time.Format(time.RFC3339Nano))
+	}
+	lines = append(lines, "level "+r.Level.String(), "msg   "+r.Message, "")
+	r.Attrs(func(a slog.Attr) bool {
+		lines = appendDetail(lines, a, "")
+		return true
+	})
+	return lines
+}
+
+// appendDetail дописывает атрибут; группы раскрываются с отступом
+func appendDetail(lines []string, a slog.Attr, indent string) []string {
+	v := a.Value
+	if v.Kind() == slog.KindGroup {
+		lines = append(lines, indent+a.Key+":")
+		for _, ga := range v.Group() {
+			lines = appendDetail(lines, ga, indent+"  ")
+		}
+		return lines
+	}
+
+	s := valueString(v)
+	if v.Kind() == slog.KindString && len(s) > 0 && (s[0] == '{' || s[0] == '[') {
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, []byte(s), indent+"  ", "  ") == nil {
+			return append(lines, indent+a.Key+": "+pretty.String())
+		}
+	}
+	return append(lines, indent+a.Key+": "+s)
+}
+
+// wrapLines разбивает строки по ширине панели
+func wrapLines(lines []string, width int) [][]rune {
+	var out [][]rune
+	for _, l := range lines {
+		for _, part := range strings.Split(l, "\n") {
+			rs := sanitize(part)
+			for len(rs) > width {
+				out = append(out, rs[:width])
+				rs = rs[width:]
+			}
+			out = append(out, rs)
+		}
+	}
+	return out
+}
+
+// ──────────────────────────────────────────────────────────
+// Цикл событий
+// ──────────────────────────────────────────────────────────
+
+// runTUI открывает интерактивный просмотр файла name. Файл читается
+// как при -f, поэтому новые записи появляются сразу.
+func runTUI(name string, f filter, follow bool) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	term, err := openTerminal(tty)
+	if err != nil {
+		return err
+	}
+	defer term.restore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fr, err := openFollow(ctx, name, followPoll)
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+
+	batches := make(chan []entry)
+	go readEntries(ctx, fr, newRenderer(f), batches)
+
+	keys := make(chan []byte)
+	go func() {
+		for {
+			buf := make([]byte, 64)
+			n, err := tty.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- buf[:n]
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	if len(resizeSignals) > 0 {
+		signal.Notify(resize, resizeSignals...)
+		defer signal.Stop(resize)
+	}
+
+	b := newBrowser(filepath.Base(name))
+	b.follow = follow
+	if w, h, err := term.size(); err == nil && w > 0 && h > 0 {
+		b.width, b.height = w, h
+	}
+
+	out := bufio.NewWriter(tty)
+	// Альтернативный экран и скрытый курсор; при выходе - обратно
+	out.WriteString("\x1b[?1049h\x1b[?25l\x1b[2J")
+	defer func() {
+		out.WriteString("\x1b[?25h\x1b[?1049l")
+		out.Flush()
+	}()
+
+	for {
+		if err := b.draw(out); err != nil {
+			return err
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+
+		select {
+		case batch := <-batches:
+			b.add(batch...)
+		case data, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range parseKeys(data) {
+				if b.handleKey(k) {
+					return nil
+				}
+			}
+		case <-resize:
+			if w, h, err := term.size(); err == nil && w > 0 && h > 0 {
+				b.width, b.height = w, h
+				b.setCursor(b.cursor)
+				out.WriteString("\x1b[2J")
+			}
+		}
+	}
+}
+
+// readEntries читает строки из r и отправляет их пачками: пачка уходит,
+// когда прочитанные данные закончились или набралось tuiBatch строк
+func readEntries(ctx context.Context, r io.Reader, rd *renderer, out chan<- []entry) {
+	const tuiBatch = 4096
+
+	br := bufio.NewReaderSize(r, 64<<10)
+	var batch []entry
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if e, ok := rd.entry(line); ok {
+				batch = append(batch, e)
+			}
+		}
+		if len(batch) > 0 && (err != nil || br.Buffered() == 0 || len(batch) >= tuiBatch) {
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return
+			}
+			batch = nil
+		}
+		if err != nil {
+			return
+		}
+	}
+}
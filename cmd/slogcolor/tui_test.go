@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestBrowser создает браузер 60x6 с записями из строк лога
+func newTestBrowser(t *testing.T, lines ...string) *browser {
+	t.Helper()
+	b := newBrowser("app.log")
+	b.width, b.height = 60, 6
+	rd := newRenderer(filter{})
+	for _, line := range lines {
+		e, ok := rd.entry([]byte(line))
+		if !ok {
+			t.Fatalf("строка %q отброшена", line)
+		}
+		b.add(e)
+	}
+	return b
+}
+
+// cursorLine возвращает исходную строку под курсором
+func cursorLine(b *browser) string {
+	e, _ := b.current()
+	return e.line
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("jq\x1b[A\x1b[6~\x1bOH\r\x7f/ы\x1b\x03\x1b[99Z"))
+	want := []key{"j", "q", keyUp, keyPgDn, keyHome, keyEnter, keyBackspace, "/", "ы", keyEsc, keyCtrlC}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys = %q, ожидалось %q", got, want)
+	}
+}
+
+func TestAppendCell(t *testing.T) {
+	s := []rune("\x1b[94mhello\x1b[0m world")
+
+	got, n := appendCell(nil, s, 8, nil)
+	if want := "\x1b[94mhello\x1b[0m wo\x1b[0m"; string(got) != want || n != 8 {
+		t.Errorf("обрезка: %q (%d), ожидалось %q", got, n, want)
+	}
+
+	// Подсветка "lo w" переживает сброс цвета внутри диапазона
+	got, _ = appendCell(nil, s, 20, findAll(stripANSI(s), []rune("LO W")))
+	want := "\x1b[94mhel\x1b[7mlo\x1b[0m\x1b[7m w\x1b[27morld\x1b[0m"
+	if string(got) != want {
+		t.Errorf("подсветка:\n%q\nожидалось\n%q", got, want)
+	}
+}
+
+func TestRenderer_StripsLogEscapes(t *testing.T) {
+	rd := newRenderer(filter{})
+	lines := []string{
+		// OSC 52 (буфер обмена) и заголовок окна в сообщении записи
+		`{"level":"INFO","msg":"a\u001b]52;c;ZXZpbA==\u0007b","k":"\u001b]0;title\u001b\\v\u001b[2J"}`,
+		// Неразобранная строка с OSC, очисткой экрана и цветом
+		"raw \x1b]0;title\x07text\x1b[2J \x1b[31mred\x1b[0m \x1b\x01end",
+	}
+	want := []string{"INF ab k=v", "raw text red   end"}
+	for i, line := range lines {
+		e, ok := rd.entry([]byte(line))
+		if !ok {
+			t.Fatalf("строка %q отброшена", line)
+		}
+		if plain := string(stripANSI(e.text)); !strings.HasSuffix(plain, want[i]) {
+			t.Errorf("видимый текст %q, ожидалось окончание %q", plain, want[i])
+		}
+		// Остались только SGR, выведенные ColorHandler'ом
+		for j, r := range e.text {
+			if r == '\x1b' && !isSGR(string(e.text[j:])) {
+				t.Errorf("в строке осталась не-SGR последовательность: %q", string(e.text))
+				break
+			}
+		}
+		if i == 1 && strings.Contains(string(e.text), "\x1b[31m") {
+			t.Errorf("цвета из неразобранной строки должны удаляться: %q", string(e.text))
+		}
+	}
+}
+
+func TestBrowser_Navigation(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, fmt.Sprintf("level=INFO msg=m%d", i))
+	}
+	b := newTestBrowser(t, lines...)
+
+	b.handleKey(keyPgDn)
+	if b.cursor != 4 || b.top != 0 {
+		t.Errorf("PgDn: cursor=%d top=%d", b.cursor, b.top)
+	}
+	b.handleKey("G")
+	if b.cursor != 19 || b.top != 15 {
+		t.Errorf("G: cursor=%d top=%d", b.cursor, b.top)
+	}
+	b.handleKey("k")
+	if b.cursor != 18 || b.top != 15 {
+		t.Errorf("k: cursor=%d top=%d", b.cursor, b.top)
+	}
+	b.handleKey("g")
+	if b.cursor != 0 || b.top != 0 {
+		t.Errorf("g: cursor=%d top=%d", b.cursor, b.top)
+	}
+	if b.handleKey("q") != true {
+		t.Error("q должен завершать просмотр")
+	}
+}
+
+func TestBrowser_Follow(t *testing.T) {
+	b := newTestBrowser(t, "msg=a", "msg=b")
+	b.handleKey("f")
+	if !b.follow || cursorLine(b) != "msg=b" {
+		t.Fatalf("f: follow=%v, курсор на %q", b.follow, cursorLine(b))
+	}
+
+	rd := newRenderer(filter{})
+	e, _ := rd.entry([]byte("msg=c\n"))
+	b.add(e)
+	if cursorLine(b) != "msg=c" {
+		t.Errorf("новая запись не выбрана в режиме слежения: %q", cursorLine(b))
+	}
+
+	b.handleKey(keyUp)
+	if b.follow {
+		t.Error("движение вверх должно выключать слежение")
+	}
+}
+
+func TestBrowser_LevelToggle(t *testing.T) {
+	b := newTestBrowser(t,
+		"level=DEBUG msg=d",
+		"level=INFO msg=i",
+		"level=ERROR msg=e",
+		"\tat main.go:10",
+		"level=WARN msg=w",
+	)
+	b.setCursor(2)
+
+	b.handleKey("e")
+	var got []string
+	for _, i := range b.visible {
+		got = append(got, b.entries[i].line)
+	}
+	if want := []string{"level=DEBUG msg=d", "level=INFO msg=i", "level=WARN msg=w"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("после скрытия ERROR: %q", got)
+	}
+	if cursorLine(b) != "level=WARN msg=w" {
+		t.Errorf("курсор должен перейти к следующей видимой записи, а стоит на %q", cursorLine(b))
+	}
+	if !strings.Contains(b.status(), "DIWe") {
+		t.Errorf("статус не показывает скрытый уровень: %q", b.status())
+	}
+
+	b.handleKey("e")
+	if len(b.visible) != 5 {
+		t.Errorf("после повторного переключения видно %d строк", len(b.visible))
+	}
+}
+
+func TestBrowser_Search(t *testing.T) {
+	b := newTestBrowser(t, "msg=start", "msg=timeout", "msg=ok", "msg=TIMEOUT again")
+
+	for _, k := range []key{"/", "t", "i", "m"} {
+		b.handleKey(k)
+	}
+	if !b.input || cursorLine(b) != "msg=timeout" {
+		t.Fatalf("инкрементальный поиск: input=%v, курсор на %q", b.input, cursorLine(b))
+	}
+	b.handleKey(keyEnter)
+
+	b.handleKey("n")
+	if cursorLine(b) != "msg=TIMEOUT again" {
+		t.Errorf("n: курсор на %q", cursorLine(b))
+	}
+	b.handleKey("n")
+	if cursorLine(b) != "msg=timeout" {
+		t.Errorf("n должен переходить к началу: курсор на %q", cursorLine(b))
+	}
+	b.handleKey("N")
+	if cursorLine(b) != "msg=TIMEOUT again" {
+		t.Errorf("N: курсор на %q", cursorLine(b))
+	}
+
+	// Esc во время ввода возвращает прежний запрос и позицию
+	for _, k := range []key{"/", "z", keyEsc} {
+		b.handleKey(k)
+	}
+	if string(b.query) != "tim" || cursorLine(b) != "msg=TIMEOUT again" {
+		t.Errorf("Esc: запрос %q, курсор на %q", string(b.query), cursorLine(b))
+	}
+}
+
+func TestBrowser_Draw(t *testing.T) {
+	b := newTestBrowser(t,
+		`{"level":"INFO","msg":"request","user":{"id":7},"body":"{\"a\":[1,2]}"}`,
+		"plain line",
+	)
+	b.height = 14
+	b.handleKey(keyEnter)
+
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	if err := b.draw(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Flush()
+
+	rows := strings.Split(out.String(), "\r\n")
+	if len(rows) != b.height {
+		t.Fatalf("строк на экране: %d, ожидалось %d", len(rows), b.height)
+	}
+	screen := string(stripANSI([]rune(out.String())))
+	for _, want := range []string{"▸ [00:00:00] INF request", "plain line", "user:", "  id: 7", `body: {`, `"a": [`, "app.log  1/2"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("на экране нет %q:\n%s", want, screen)
+		}
+	}
+	if strings.Contains(screen, "time ") {
+		t.Error("нулевое время не должно показываться в панели")
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ContextExtractor возвращает атрибуты, которые нужно добавить к записи
+// из ее контекста: request_id, tenant и т.п. Вызывается на каждую запись.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// contextAttrsKey - ключ атрибутов из ContextWithAttrs
+type contextAttrsKey struct{}
+
+// ContextWithAttrs возвращает контекст, атрибуты которого ColorHandler
+// добавляет к каждой записи, созданной с ним (InfoContext и т.п.).
+// Атрибуты дописываются к уже сохраненным в ctx.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	prev := AttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(prev)+len(attrs))
+	merged = append(merged, prev...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, contextAttrsKey{}, merged)
+}
+
+// AttrsFromContext возвращает атрибуты, сохраненные ContextWithAttrs
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextAttrs возвращает атрибуты из контекста: сначала из
+// ContextWithAttrs, затем от ContextExtractors. Они относятся ко всей
+// записи, поэтому выводятся вне групп из WithGroup.
+func (h *ColorHandler) contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs := AttrsFromContext(ctx)
+	for _, extract := range h.ContextExtractors {
+		if extra := extract(ctx); len(extra) > 0 {
+			// Срез из контекста общий: дописываем только в копию
+			attrs = append(attrs[:len(attrs):len(attrs)], extra...)
+		}
+	}
+	return attrs
+}
+
+// withAttrs возвращает запись с добавленными attrs. Запись копируется,
+// только если атрибуты есть: исходный Record может разделяться с вызывающим.
+func withAttrs(r slog.Record, attrs []slog.Attr) slog.Record {
+	if len(attrs) == 0 {
+		return r
+	}
+	r = r.Clone()
+	r.AddAttrs(attrs...)
+	return r
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestContextWithAttrs(t *testing.T) {
+	ctx := ContextWithAttrs(context.Background(), slog.String("request_id", "r-1"))
+	ctx = ContextWithAttrs(ctx, slog.Int("user", 7))
+
+	h, buf := newTestHandler()
+	slog.New(h).InfoContext(ctx, "m", "k", "v")
+
+	if want := " INF m k=v request_id=r-1 user=7\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("атрибуты из контекста:\n%q\nожидалось окончание\n%q", buf.String(), want)
+	}
+
+	// Родительский контекст не меняется
+	parent := ContextWithAttrs(context.Background(), slog.String("a", "1"))
+	_ = ContextWithAttrs(parent, slog.String("b", "2"))
+	if got := AttrsFromContext(parent); len(got) != 1 {
+		t.Errorf("родительский контекст изменился: %v", got)
+	}
+	if ContextWithAttrs(parent) != parent {
+		t.Error("без атрибутов контекст должен возвращаться как есть")
+	}
+}
+
+func TestContextExtractors(t *testing.T) {
+	h, buf := newTestHandler()
+	h.Format = FormatJSON
+	h.ContextExtractors = []ContextExtractor{
+		func(ctx context.Context) []slog.Attr {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				return []slog.Attr{slog.String("tenant", tenant)}
+			}
+			return nil
+		},
+	}
+	log := slog.New(h).WithGroup("req")
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	log.InfoContext(ContextWithAttrs(ctx, slog.String("id", "r-2")), "m")
+	log.Info("plain")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], `"msg":"m","id":"r-2","tenant":"acme"`) || strings.Contains(lines[0], `"req"`) {
+		t.Errorf("атрибуты из контекста должны выводиться вне групп: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "tenant") {
+		t.Errorf("без значения в контексте экстрактор ничего не добавляет: %s", lines[1])
+	}
+}
+
+func TestContextAttrs_OutsideGroups(t *testing.T) {
+	ctx := ContextWithAttrs(context.Background(), slog.String("rid", "1"))
+	tests := map[Format]string{
+		FormatJSON:   `"msg":"m","rid":"1","g":{"a":"b","x":1}}`,
+		FormatLogfmt: ` msg=m g.a=b g.x=1 rid=1`,
+	}
+	for format, want := range tests {
+		h, buf := newTestHandler()
+		h.Format = format
+		slog.New(h).WithGroup("g").With("a", "b").InfoContext(ctx, "m", "x", 1)
+
+		if !strings.HasSuffix(buf.String(), want+"\n") {
+			t.Errorf("формат %d:\n%s\nожидалось окончание\n%s", format, buf.String(), want)
+		}
+	}
+}
+
+func TestContextAttrs_Hook(t *testing.T) {
+	h, _ := newTestHandler()
+	var got []string
+	h.SetHook(func(ctx context.Context, r slog.Record) {
+		r.Attrs(func(a slog.Attr) bool {
+			got = append(got, a.Key)
+			return true
+		})
+	})
+
+	r := newTestRecord(slog.LevelError, "m")
+	r.AddAttrs(slog.Int("n", 1))
+	ctx := ContextWithAttrs(context.Background(), slog.String("request_id", "r-3"))
+	if err := h.Handle(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(got, ",") != "n,request_id" {
+		t.Errorf("хук должен видеть атрибуты из контекста: %v", got)
+	}
+	// Исходная запись не изменилась
+	if r.NumAttrs() != 1 {
+		t.Errorf("Handle изменил запись вызывающего: %d атрибутов", r.NumAttrs())
+	}
+}
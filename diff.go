@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/fatih/color"
+)
+
+// Цвета строк диффа
+var (
+	diffRemoveStyle = NewStyle(color.FgRed)
+	diffAddStyle    = NewStyle(color.FgGreen)
+)
+
+// Diff возвращает атрибут, который ColorHandler выводит как дифф по полям:
+// в строке записи - число изменений, под ней - удаленные (красным)
+// и добавленные (зеленым) значения. Значения сравниваются по их
+// JSON-представлению; вложенные поля записываются путями вида
+// "db.hosts[1]". В JSON и logfmt дифф выводится объектом
+// {"путь": {"old": ..., "new": ...}}.
+func Diff(key string, before, after any) slog.Attr {
+	return slog.Any(key, diffValue{before: before, after: after})
+}
+
+// diffValue - значение атрибута из Diff
+type diffValue struct {
+	before, after any
+}
+
+// diffLeaf - скалярное значение (или пустой объект/массив) по пути
+type diffLeaf struct {
+	path  string
+	value string // компактный JSON
+}
+
+// diffChange - изменение одного поля; пустой old или new означает,
+// что поле добавлено или удалено
+type diffChange struct {
+	path     string
+	old, new string
+}
+
+// changes сравнивает значения и возвращает изменения: сначала измененные
+// и удаленные поля в порядке before, затем добавленные в порядке after
+func (d diffValue) changes() []diffChange {
+	before, after := flattenValue(d.before), flattenValue(d.after)
+
+	afterValues := make(map[string]string, len(after))
+	for _, l := range after {
+		afterValues[l.path] = l.value
+	}
+	seen := make(map[string]bool, len(before))
+
+	var changes []diffChange
+	for _, l := range before {
+		seen[l.path] = true
+		if v, ok := afterValues[l.path]; !ok || v != l.value {
+			changes = append(changes, diffChange{path: l.path, old: l.value, new: v})
+		}
+	}
+	for _, l := range after {
+		if !seen[l.path] {
+			changes = append(changes, diffChange{path: l.path, new: l.value})
+		}
+	}
+	return changes
+}
+
+// MarshalJSON выводит изменения объектом для форматов JSON и logfmt
+func (d diffValue) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, c := range d.changes() {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendQuote(buf, c.path)
+		buf = append(buf, ":{"...)
+		if c.old != "" {
+			buf = append(buf, `"old":`...)
+			buf = append(buf, c.old...)
+		}
+		if c.new != "" {
+			if c.old != "" {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `"new":`...)
+			buf = append(buf, c.new...)
+		}
+		buf = append(buf, '}')
+	}
+	return append(buf, '}'), nil
+}
+
+// flattenValue раскладывает значение на скаляры с путями, сохраняя порядок
+// полей структур. Значение, которое не сериализуется в JSON, становится
+// одним скаляром с текстом fmt.
+func flattenValue(v any) []diffLeaf {
+	data, err := json.Marshal(v)
+	if err == nil {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var leaves []diffLeaf
+		if err = flattenJSON(dec, "", &leaves); err == nil {
+			return leaves
+		}
+	}
+	text, _ := json.Marshal(fmt.Sprintf("%+v", v))
+	return []diffLeaf{{value: string(text)}}
+}
+
+// flattenJSON читает одно значение из dec и дописывает его скаляры в out
+func flattenJSON(dec *json.Decoder, path string, out *[]diffLeaf) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		n := 0
+		for ; dec.More(); n++ {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			name := key.(string)
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := flattenJSON(dec, name, out); err != nil {
+				return err
+			}
+		}
+		if n == 0 {
+			*out = append(*out, diffLeaf{path, "{}"})
+		}
+	case json.Delim('['):
+		n := 0
+		for ; dec.More(); n++ {
+			if err := flattenJSON(dec, path+"["+strconv.Itoa(n)+"]", out); err != nil {
+				return err
+			}
+		}
+		if n == 0 {
+			*out = append(*out, diffLeaf{path, "[]"})
+		}
+	default:
+		// json.Number, string, bool или nil
+		text, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		*out = append(*out, diffLeaf{path, string(text)})
+		return nil
+	}
+
+	// Закрывающая скобка
+	_, err = dec.Token()
+	return err
+}
+
+// diffOf возвращает значение из Diff
+func diffOf(v slog.Value) (diffValue, bool) {
+	if v.Kind() != slog.KindAny {
+		return diffValue{}, false
+	}
+	d, ok := v.Any().(diffValue)
+	return d, ok
+}
+
+// appendDiff выводит в строке записи число изменений, а сами изменения -
+// в blocks, под записью. Стиль значения уже открыт вызывающим.
+func appendDiff(buf, blocks *buffer, key string, d diffValue, colored bool) {
+	changes := d.changes()
+	buf.WriteByte('[')
+	switch len(changes) {
+	case 0:
+		buf.WriteString("no changes]")
+		return
+	case 1:
+		buf.WriteString("1 change]")
+	default:
+		*buf = strconv.AppendInt(*buf, int64(len(changes)), 10)
+		buf.WriteString(" changes]")
+	}
+
+	blocks.WriteString("\n  ")
+	blocks.setStyle(keyStyle, colored)
+	blocks.WriteString(key)
+	blocks.WriteByte(':')
+	blocks.resetStyle(colored)
+
+	line := func(sign byte, s Style, path, value string) {
+		blocks.WriteString("\n  ")
+		blocks.setStyle(s, colored)
+		blocks.WriteByte(sign)
+		blocks.WriteByte(' ')
+		if path != "" {
+			blocks.WriteString(path)
+			blocks.WriteString(": ")
+		}
+		blocks.WriteString(value)
+		blocks.resetStyle(colored)
+	}
+	for _, c := range changes {
+		if c.old != "" {
+			line('-', diffRemoveStyle, c.path, c.old)
+		}
+		if c.new != "" {
+			line('+', diffAddStyle, c.path, c.new)
+		}
+	}
+}
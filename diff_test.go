@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type dbConfig struct {
+	Host  string   `json:"host"`
+	Port  int      `json:"port"`
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+type appConfig struct {
+	Name  string    `json:"name"`
+	DB    dbConfig  `json:"db"`
+	Debug *bool     `json:"debug,omitempty"`
+	Limit []float64 `json:"limit"`
+}
+
+func TestDiff_Changes(t *testing.T) {
+	debug := true
+	before := appConfig{Name: "api", DB: dbConfig{Host: "db", Port: 5432, Hosts: []string{"a", "b"}}, Limit: []float64{}}
+	after := appConfig{Name: "api", DB: dbConfig{Host: "db", Port: 6432, Hosts: []string{"a"}}, Debug: &debug, Limit: []float64{1.5}}
+
+	got := diffValue{before, after}.changes()
+	want := []diffChange{
+		{path: "db.port", old: "5432", new: "6432"},
+		{path: "db.hosts[1]", old: `"b"`},
+		{path: "limit", old: "[]"},
+		{path: "debug", new: "true"},
+		{path: "limit[0]", new: "1.5"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("изменения: %+v, ожидалось %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("изменение %d: %+v, ожидалось %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDiff_Render(t *testing.T) {
+	before := map[string]any{"port": 5432, "host": "db"}
+	after := map[string]any{"port": 6432, "host": "db", "tls": true}
+
+	got := renderValue(t, FormatColor, diffValue{before, after})
+	want := "[12:30:45] INF m v=[2 changes]\n" +
+		"  v:\n" +
+		"  - port: 5432\n" +
+		"  + port: 6432\n" +
+		"  + tls: true"
+	if got != want {
+		t.Errorf("дифф:\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestDiff_Scalars(t *testing.T) {
+	h, buf := newTestHandler()
+	slog.New(h).Info("m", Diff("v", 1, 2), Diff("same", "x", "x"))
+
+	// Для скаляров путь не выводится
+	if want := " INF m v=[1 change] same=[no changes]\n  v:\n  - 1\n  + 2\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("дифф скаляров:\n%q\nожидалось окончание\n%q", buf.String(), want)
+	}
+}
+
+func TestDiff_Colors(t *testing.T) {
+	withColors(t)
+	got := renderValue(t, FormatColor, diffValue{map[string]int{"a": 1}, map[string]int{"a": 2}})
+
+	for _, want := range []string{
+		string(diffRemoveStyle) + "- a: 1" + ansiReset,
+		string(diffAddStyle) + "+ a: 2" + ansiReset,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("нет %q в %q", want, got)
+		}
+	}
+}
+
+func TestDiff_OtherFormats(t *testing.T) {
+	d := diffValue{map[string]int{"a": 1, "b": 2}, map[string]int{"a": 3, "c": 4}}
+
+	if got := renderValue(t, FormatJSON, d); !strings.Contains(got, `"v":{"a":{"old":1,"new":3},"b":{"old":2},"c":{"new":4}}`) {
+		t.Errorf("JSON: %q", got)
+	}
+	if got := renderValue(t, FormatLogfmt, d); !strings.Contains(got, `v="{\"a\":{\"old\":1,\"new\":3}`) {
+		t.Errorf("logfmt: %q", got)
+	}
+}
+
+func TestDiff_WithAttrs(t *testing.T) {
+	h, buf := newTestHandler()
+	slog.New(h).With(Diff("cfg", 1, 2)).Info("m")
+
+	if !strings.HasSuffix(buf.String(), "cfg=[1 change]\n  cfg:\n  - 1\n  + 2\n") {
+		t.Errorf("дифф из With: %q", buf.String())
+	}
+}
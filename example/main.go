@@ -203,7 +203,22 @@ func main() {
 		}
 	})
 
+	// Атрибуты из контекста выводятся в каждой строке: сохраненные через
+	// ContextWithAttrs - всегда, остальные - через ContextExtractors
+	const tenantKey ctxKey = "tenant"
+	contextHandler.ContextExtractors = []logger.ContextExtractor{
+		func(ctx context.Context) []slog.Attr {
+			if tenant, ok := ctx.Value(tenantKey).(string); ok {
+				return []slog.Attr{slog.String("tenant", tenant)}
+			}
+			return nil
+		},
+	}
+	ctx = context.WithValue(ctx, tenantKey, "acme")
+	ctx = logger.ContextWithAttrs(ctx, slog.String("request_id", "req-ctx-777"))
+
 	contextLog := slog.New(contextHandler)
+	contextLog.InfoContext(ctx, "запрос принят", "endpoint", "/api/payments")
 	contextLog.ErrorContext(ctx, "ошибка в обработчике запроса", "endpoint", "/api/payments", "status", 500)
 
 	fmt.Println()
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// formattedValue - результат пользовательского или встроенного
+// форматтера; выводится как обычная строка, без распознавания JSON
+type formattedValue string
+
+// Formatter - функция вывода значений одного типа для slog.Any;
+// создается NewFormatter и задается в ColorHandler.Formatters
+type Formatter struct {
+	typ reflect.Type
+	fn  func(v any) (string, bool)
+}
+
+// NewFormatter создает форматтер значений типа T. T - конкретный тип
+// (net.IP) или интерфейс (fmt.Stringer, proto.Message).
+func NewFormatter[T any](fn func(v T) string) Formatter {
+	return Formatter{
+		typ: reflect.TypeFor[T](),
+		fn:  func(v any) (string, bool) { return fn(v.(T)), true },
+	}
+}
+
+// builtinFormatters проверяются после форматтеров handler'а: типы с методом
+// String или MarshalText (net.IP, uuid.UUID, decimal.Decimal) выводятся
+// текстом, а не JSON-представлением своих полей
+var builtinFormatters = []Formatter{
+	NewFormatter(func(s fmt.Stringer) string { return s.String() }),
+	{
+		typ: reflect.TypeFor[encoding.TextMarshaler](),
+		fn: func(v any) (string, bool) {
+			text, err := v.(encoding.TextMarshaler).MarshalText()
+			return string(text), err == nil
+		},
+	},
+}
+
+// formatWith выводит значение подходящим форматтером: сначала для
+// конкретного типа из fs, затем интерфейсным из fs в порядке среза, затем
+// встроенным. Паника в форматтере (например, String у nil-указателя)
+// не роняет логирование: значение выводится дальше как без форматтера.
+func formatWith(fs []Formatter, value any) (formattedValue, bool) {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		return "", false
+	}
+
+	for _, f := range fs {
+		if f.typ == t {
+			if s, ok := f.call(value); ok {
+				return s, true
+			}
+		}
+	}
+	for _, f := range fs {
+		if f.typ.Kind() == reflect.Interface && t.Implements(f.typ) {
+			if s, ok := f.call(value); ok {
+				return s, true
+			}
+		}
+	}
+	if isRawJSON(value) {
+		return "", false
+	}
+	for _, f := range builtinFormatters {
+		if t.Implements(f.typ) {
+			if s, ok := f.call(value); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// hasFormatter сообщает, есть ли для значения форматтер, не вызывая его
+func hasFormatter(fs []Formatter, value any) bool {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		return false
+	}
+	for _, f := range fs {
+		if f.typ == t || f.typ.Kind() == reflect.Interface && t.Implements(f.typ) {
+			return true
+		}
+	}
+	if isRawJSON(value) {
+		return false
+	}
+	for _, f := range builtinFormatters {
+		if t.Implements(f.typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRawJSON отделяет json.RawMessage от встроенных форматтеров: у него
+// есть метод String, но выводить его нужно как JSON
+func isRawJSON(value any) bool {
+	_, ok := value.(json.RawMessage)
+	return ok
+}
+
+func (f Formatter) call(v any) (s formattedValue, ok bool) {
+	defer func() {
+		if recover() != nil {
+			s, ok = "", false
+		}
+	}()
+	str, ok := f.fn(v)
+	return formattedValue(str), ok
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+)
+
+// renderValue выводит атрибут v без цветов в заданном формате
+func renderValue(t *testing.T, format Format, v any, fs ...Formatter) string {
+	t.Helper()
+	h, buf := newTestHandler()
+	h.Format = format
+	h.Formatters = fs
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.Any("v", v))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+type textOnly struct{ id int }
+
+func (t textOnly) MarshalText() ([]byte, error) {
+	return []byte("id-" + string(rune('0'+t.id))), nil
+}
+
+type failingText struct{ A int }
+
+func (failingText) MarshalText() ([]byte, error) { return nil, errors.New("no text") }
+
+type point struct{ X, Y int }
+
+type shape interface{ Area() int }
+
+type square struct{ Side int }
+
+func (s square) Area() int      { return s.Side * s.Side }
+func (s square) String() string { return "square" }
+
+type nilStringer struct{ name string }
+
+func (n *nilStringer) String() string { return n.name }
+
+func TestFormatters_Builtin(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{net.ParseIP("192.0.2.1"), "v=192.0.2.1"},
+		{textOnly{7}, "v=id-7"},
+		{failingText{A: 1}, "v={1}"},
+		{(*nilStringer)(nil), "v=null"},
+	}
+	for _, tt := range tests {
+		if got := renderValue(t, FormatColor, tt.v); !strings.HasSuffix(got, tt.want) {
+			t.Errorf("%T: %q, ожидалось окончание %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFormatters_AllFormats(t *testing.T) {
+	ip := net.ParseIP("2001:db8::1")
+
+	tests := map[Format]string{
+		FormatColor:  " v=2001:db8::1",
+		FormatJSON:   `"v":"2001:db8::1"`,
+		FormatLogfmt: " v=2001:db8::1",
+	}
+	for format, want := range tests {
+		if got := renderValue(t, format, ip); !strings.Contains(got, want) {
+			t.Errorf("формат %d: %q, ожидалось %q", format, got, want)
+		}
+	}
+}
+
+func TestFormatters_Handler(t *testing.T) {
+	fs := []Formatter{
+		NewFormatter(func(p point) string { return "(1,2)" }),
+		NewFormatter(func(s shape) string { return "shape" }),
+	}
+
+	if got := renderValue(t, FormatColor, point{1, 2}, fs...); !strings.HasSuffix(got, "v=(1,2)") {
+		t.Errorf("конкретный тип: %q", got)
+	}
+	// Интерфейс из Formatters важнее встроенного fmt.Stringer
+	if got := renderValue(t, FormatColor, square{2}, fs...); !strings.HasSuffix(got, "v=shape") {
+		t.Errorf("интерфейс: %q", got)
+	}
+
+	// Форматтер конкретного типа важнее интерфейсного, даже если стоит позже
+	fs = append(fs, NewFormatter(func(s square) string { return "square 2x2" }))
+	if got := renderValue(t, FormatColor, square{2}, fs...); !strings.HasSuffix(got, "v=square 2x2") {
+		t.Errorf("конкретный тип поверх интерфейса: %q", got)
+	}
+
+	// Форматтеры принадлежат handler'у: другие handler'ы их не видят
+	if got := renderValue(t, FormatColor, square{2}); !strings.HasSuffix(got, "v=square") {
+		t.Errorf("handler без Formatters: %q", got)
+	}
+
+	// Производные handler'ы наследуют Formatters
+	h, buf := newTestHandler()
+	h.Format = FormatJSON
+	h.Formatters = fs
+	slog.New(h).WithGroup("g").With("a", point{}).Info("m")
+	if !strings.Contains(buf.String(), `"a":"(1,2)"`) {
+		t.Errorf("WithGroup/WithAttrs потеряли Formatters: %q", buf.String())
+	}
+}
+
+func TestFormatters_Theme(t *testing.T) {
+	withColors(t)
+	theme := KindTheme()
+	if got := theme.valueStyle(slog.AnyValue(net.ParseIP("192.0.2.1")), nil); got != theme.String {
+		t.Errorf("значение с форматтером должно иметь стиль строки, получено %q", got)
+	}
+	if got := theme.valueStyle(slog.AnyValue(point{}), nil); got != theme.JSON {
+		t.Errorf("значение без форматтера должно иметь стиль JSON, получено %q", got)
+	}
+}
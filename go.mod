@@ -2,10 +2,15 @@ module github.com/golub15/slog_color
 
 go 1.25.5
 
-require github.com/fatih/color v1.18.0
+require (
+	github.com/fatih/color v1.18.0
+	go.opentelemetry.io/otel/trace v1.46.0
+	golang.org/x/sys v0.25.0
+)
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	go.opentelemetry.io/otel v1.46.0 // indirect
 )
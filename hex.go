@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+
+	"github.com/fatih/color"
+)
+
+// hexInlineMax - срезы не длиннее выводятся hex-строкой прямо в записи,
+// более длинные - дампом под ней
+const hexInlineMax = 32
+
+// hexDumpWidth - байт в строке дампа, как у hexdump -C
+const hexDumpWidth = 16
+
+// dumpDimStyle - смещения и непечатаемые байты дампа
+var dumpDimStyle = NewStyle(color.FgHiBlack)
+
+// hexDumpLimit возвращает лимит байт для hex-вывода или -1, если он выключен
+func (h *ColorHandler) hexDumpLimit() int {
+	switch {
+	case h.HexDumpLimit < 0:
+		return -1
+	case h.HexDumpLimit == 0:
+		return DefaultHexDumpLimit
+	}
+	return h.HexDumpLimit
+}
+
+// hexBytes возвращает срез, если значение нужно вывести в hex: только
+// []byte без форматтера handler'а (json.RawMessage - другой тип)
+func (h *ColorHandler) hexBytes(v slog.Value) ([]byte, bool) {
+	if v.Kind() != slog.KindAny || h.hexDumpLimit() < 0 {
+		return nil, false
+	}
+	data, ok := v.Any().([]byte)
+	if !ok || hasFormatter(h.Formatters, v.Any()) {
+		return nil, false
+	}
+	return data, true
+}
+
+// appendHex дописывает короткий срез hex-строкой, а для длинного выводит
+// в строке только размер, а сам дамп - в blocks. Стиль vs уже открыт.
+func (h *ColorHandler) appendHex(buf, blocks *buffer, key string, data []byte, vs Style, colored bool) {
+	limit := h.hexDumpLimit()
+	if len(data) <= hexInlineMax || blocks == nil {
+		shown := data[:min(len(data), limit, hexInlineMax)]
+		*buf = hex.AppendEncode(*buf, shown)
+		if len(shown) < len(data) {
+			buf.WriteString("…+")
+			*buf = strconv.AppendInt(*buf, int64(len(data)-len(shown)), 10)
+		}
+		return
+	}
+
+	buf.WriteByte('[')
+	*buf = strconv.AppendInt(*buf, int64(len(data)), 10)
+	buf.WriteString(" bytes]")
+	appendHexDump(blocks, key, data, limit, vs, colored)
+}
+
+// appendHexDump дописывает блок в духе hexdump -C: заголовок с ключом,
+// затем строки "смещение  hex-байты  |ASCII|". Каждая строка блока
+// начинается с перевода строки, чтобы блок выводился под записью.
+func appendHexDump(blocks *buffer, key string, data []byte, limit int, vs Style, colored bool) {
+	blocks.WriteString("\n  ")
+	blocks.setStyle(keyStyle, colored)
+	blocks.WriteString(key)
+	blocks.WriteByte(':')
+	blocks.resetStyle(colored)
+
+	shown := data[:min(len(data), limit)]
+	for off := 0; off < len(shown); off += hexDumpWidth {
+		line := shown[off:min(off+hexDumpWidth, len(shown))]
+
+		blocks.WriteString("\n  ")
+		blocks.setStyle(dumpDimStyle, colored)
+		*blocks = appendHexOffset(*blocks, off)
+		blocks.resetStyle(colored)
+		blocks.WriteString("  ")
+
+		cur := Style("")
+		set := func(s Style) {
+			if colored && s != cur {
+				if cur != "" {
+					blocks.WriteString(ansiReset)
+				}
+				blocks.WriteString(string(s))
+				cur = s
+			}
+		}
+		for i := range hexDumpWidth {
+			if i == hexDumpWidth/2 {
+				blocks.WriteByte(' ')
+			}
+			if i >= len(line) {
+				blocks.WriteString("   ")
+				continue
+			}
+			set(byteStyle(line[i], vs))
+			*blocks = hex.AppendEncode(*blocks, line[i:i+1])
+			blocks.WriteByte(' ')
+		}
+
+		set(vs)
+		blocks.WriteString(" |")
+		for _, c := range line {
+			if isPrintableByte(c) {
+				set(vs)
+				blocks.WriteByte(c)
+			} else {
+				set(dumpDimStyle)
+				blocks.WriteByte('.')
+			}
+		}
+		set(vs)
+		blocks.WriteByte('|')
+		blocks.resetStyle(colored)
+	}
+
+	if rest := len(data) - len(shown); rest > 0 {
+		blocks.WriteString("\n  ")
+		blocks.setStyle(dumpDimStyle, colored)
+		blocks.WriteString("… +")
+		*blocks = strconv.AppendInt(*blocks, int64(rest), 10)
+		blocks.WriteString(" bytes")
+		blocks.resetStyle(colored)
+	}
+}
+
+// byteStyle приглушает нулевые и непечатаемые байты в hex-колонке
+func byteStyle(c byte, vs Style) Style {
+	if isPrintableByte(c) {
+		return vs
+	}
+	return dumpDimStyle
+}
+
+func isPrintableByte(c byte) bool {
+	return c >= 0x20 && c < 0x7f
+}
+
+// appendHexOffset дописывает смещение восемью hex-цифрами
+func appendHexOffset(dst []byte, off int) []byte {
+	const digits = "0123456789abcdef"
+	for shift := 28; shift >= 0; shift -= 4 {
+		dst = append(dst, digits[off>>shift&0xf])
+	}
+	return dst
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHex_Inline(t *testing.T) {
+	got := renderValue(t, FormatColor, []byte{0xde, 0xad, 0xbe, 0xef, 0x00})
+	if want := "[12:30:45] INF m v=deadbeef00"; got != want {
+		t.Errorf("короткий срез:\n%q\nожидалось\n%q", got, want)
+	}
+}
+
+func TestHex_Dump(t *testing.T) {
+	data := []byte("Hello, world!\n\x00\x01GET / HTTP/1.1\r\n\r\n")
+	got := renderValue(t, FormatColor, data)
+
+	want := "[12:30:45] INF m v=[34 bytes]\n" +
+		"  v:\n" +
+		"  00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 0a 00 01  |Hello, world!...|\n" +
+		"  00000010  47 45 54 20 2f 20 48 54  54 50 2f 31 2e 31 0d 0a  |GET / HTTP/1.1..|\n" +
+		"  00000020  0d 0a                                             |..|"
+	if got != want {
+		t.Errorf("дамп:\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestHex_Limit(t *testing.T) {
+	data := make([]byte, 100)
+
+	h, buf := newTestHandler()
+	h.HexDumpLimit = 40
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.Any("v", data))
+	_ = h.Handle(context.Background(), r)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("ожидалось 6 строк (запись, заголовок, 3 строки дампа, остаток), получено:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[4], "  00000020  00 00 00 00 00 00 00 00  ") {
+		t.Errorf("последняя строка дампа: %q", lines[4])
+	}
+	if lines[5] != "  … +60 bytes" {
+		t.Errorf("строка остатка: %q", lines[5])
+	}
+}
+
+func TestHex_Disabled(t *testing.T) {
+	h, buf := newTestHandler()
+	h.HexDumpLimit = NoHexDump
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.Any("v", []byte("hi")))
+	_ = h.Handle(context.Background(), r)
+
+	if want := "[12:30:45] INF m v=\"aGk=\"\n"; buf.String() != want {
+		t.Errorf("без hex:\n%q\nожидалось\n%q", buf.String(), want)
+	}
+}
+
+func TestHex_OtherFormatsKeepBase64(t *testing.T) {
+	data := []byte("hi")
+	if got := renderValue(t, FormatJSON, data); !strings.Contains(got, `"v":"aGk="`) {
+		t.Errorf("JSON: %q", got)
+	}
+	if got := renderValue(t, FormatColor, json.RawMessage(`{"a":1}`)); strings.Contains(got, "7b") {
+		t.Errorf("json.RawMessage не должен выводиться в hex: %q", got)
+	}
+}
+
+func TestHex_WithAttrs(t *testing.T) {
+	h, buf := newTestHandler()
+	logger := slog.New(h).With("frame", make([]byte, 40))
+	logger.Info("a", "id", 1)
+	logger.Info("b", "tail", make([]byte, 33))
+
+	out := buf.String()
+	if strings.Count(out, "  frame:\n") != 2 {
+		t.Errorf("дамп из With должен выводиться в каждой записи:\n%s", out)
+	}
+	// Дамп из With идет раньше дампа из записи
+	if i, j := strings.LastIndex(out, "  frame:"), strings.Index(out, "  tail:"); i < 0 || j < i {
+		t.Errorf("порядок блоков нарушен:\n%s", out)
+	}
+	if !strings.Contains(out, "frame=[40 bytes] id=1\n") {
+		t.Errorf("строка записи: %q", out)
+	}
+}
+
+func TestHex_Colors(t *testing.T) {
+	withColors(t)
+	got := renderValue(t, FormatColor, append([]byte("ab"), make([]byte, 40)...))
+
+	if !strings.Contains(got, string(valueStyle)+"61 62 "+ansiReset+string(dumpDimStyle)+"00 ") {
+		t.Errorf("печатаемые и нулевые байты должны различаться цветом: %q", got)
+	}
+	if !strings.Contains(got, string(dumpDimStyle)+"00000000"+ansiReset) {
+		t.Errorf("смещение должно быть приглушено: %q", got)
+	}
+}
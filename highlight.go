@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"cmp"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/fatih/color"
+)
+
+// Цвета лексем JSON, если они не заданы в Theme
+var (
+	jsonKeyStyle    = NewStyle(color.FgHiBlue)
+	jsonStringStyle = NewStyle(color.FgHiYellow)
+	jsonNumberStyle = NewStyle(color.FgHiCyan)
+	jsonBoolStyle   = NewStyle(color.FgHiMagenta)
+	jsonNullStyle   = NewStyle(color.FgHiBlack)
+)
+
+// appendColorValue дописывает значение, подсвечивая лексемы, если
+// formatValue вернул JSON. base - стиль значения, уже открытый вызывающим.
+func (h *ColorHandler) appendColorValue(buf *buffer, v slog.Value, base Style) {
+	if v.Kind() != slog.KindAny {
+		h.appendValue(buf, v)
+		return
+	}
+	formatted := formatValue(v, h.jsonSniffLimit(), h.Formatters)
+	if raw, ok := jsonOf(v, formatted); ok {
+		h.Theme.appendJSON(buf, raw, base)
+		return
+	}
+	appendAny(buf, formatted)
+}
+
+// jsonOf возвращает текст JSON, если formatted - json.RawMessage
+// или результат json.MarshalIndent из formatAnyValue
+func jsonOf(v slog.Value, formatted any) ([]byte, bool) {
+	switch f := formatted.(type) {
+	case json.RawMessage:
+		return f, true
+	case string:
+		if _, ok := v.Any().(string); !ok {
+			return []byte(f), true
+		}
+	}
+	return nil, false
+}
+
+// appendJSON дописывает JSON (компактный или с отступами), раскрашивая
+// ключи, строки, числа, true/false и null. Скобки, разделители и все,
+// что не удалось распознать, выводятся стилем base.
+func (t *Theme) appendJSON(buf *buffer, data []byte, base Style) {
+	cur := base
+	set := func(s Style) {
+		if s != cur {
+			buf.WriteString(ansiReset)
+			buf.WriteString(string(s))
+			cur = s
+		}
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		end := i + 1
+		switch {
+		case c == '"':
+			end = jsonStringEnd(data, i)
+			if jsonIsKey(data, end) {
+				set(cmp.Or(t.JSONKey, jsonKeyStyle))
+			} else {
+				set(cmp.Or(t.JSONString, jsonStringStyle))
+			}
+		case c == '-' || c >= '0' && c <= '9':
+			for end < len(data) && isJSONNumberByte(data[end]) {
+				end++
+			}
+			set(cmp.Or(t.JSONNumber, jsonNumberStyle))
+		case hasPrefixAt(data, i, "true"), hasPrefixAt(data, i, "false"):
+			end = i + 4
+			if c == 'f' {
+				end++
+			}
+			set(cmp.Or(t.JSONBool, jsonBoolStyle))
+		case hasPrefixAt(data, i, "null"):
+			end = i + 4
+			set(cmp.Or(t.JSONNull, jsonNullStyle))
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			// Пробелы выводятся в текущем стиле, чтобы не плодить переключения
+		default:
+			set(base)
+		}
+		buf.Write(data[i:end])
+		i = end
+	}
+	set(base)
+}
+
+// jsonStringEnd возвращает индекс за закрывающей кавычкой строки,
+// начинающейся в data[start], или len(data)
+func jsonStringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// jsonIsKey сообщает, следует ли за строкой, закончившейся перед end, двоеточие
+func jsonIsKey(data []byte, end int) bool {
+	for ; end < len(data); end++ {
+		switch data[end] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+func isJSONNumberByte(c byte) bool {
+	return c >= '0' && c <= '9' || c == '.' || c == 'e' || c == 'E' || c == '+' || c == '-'
+}
+
+func hasPrefixAt(data []byte, i int, prefix string) bool {
+	return len(data)-i >= len(prefix) && string(data[i:i+len(prefix)]) == prefix
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// highlightJSON возвращает подсвеченный JSON с базовым стилем valueStyle
+func highlightJSON(t *Theme, data string) string {
+	buf := newBuffer()
+	defer buf.Free()
+	t.appendJSON(buf, []byte(data), valueStyle)
+	return string(*buf)
+}
+
+func TestAppendJSON_Compact(t *testing.T) {
+	// Строки - своим цветом, остальные лексемы - цветами по умолчанию
+	theme := Theme{JSONString: greenStyle}
+	got := highlightJSON(&theme, `{"a":"x","n":-1.5e3,"ok":true,"z":null,"l":[false]}`)
+
+	sw := func(s Style) string { return ansiReset + string(s) }
+	want := sw(valueStyle) + "{" +
+		sw(jsonKeyStyle) + `"a"` + sw(valueStyle) + ":" + sw(greenStyle) + `"x"` + sw(valueStyle) + "," +
+		sw(jsonKeyStyle) + `"n"` + sw(valueStyle) + ":" + sw(jsonNumberStyle) + `-1.5e3` + sw(valueStyle) + "," +
+		sw(jsonKeyStyle) + `"ok"` + sw(valueStyle) + ":" + sw(jsonBoolStyle) + `true` + sw(valueStyle) + "," +
+		sw(jsonKeyStyle) + `"z"` + sw(valueStyle) + ":" + sw(jsonNullStyle) + `null` + sw(valueStyle) + "," +
+		sw(jsonKeyStyle) + `"l"` + sw(valueStyle) + ":[" + sw(jsonBoolStyle) + `false` + sw(valueStyle) + "]}"
+	// Первый set(base) ничего не выводит: base уже открыт вызывающим
+	want = strings.TrimPrefix(want, sw(valueStyle))
+
+	if got != want {
+		t.Errorf("подсветка:\n%q\nожидалось\n%q", got, want)
+	}
+}
+
+func TestAppendJSON_Indented(t *testing.T) {
+	theme := KindTheme()
+	theme.JSONString = greenStyle
+	data := "{\n  \"msg\": \"a \\\"quoted\\\": text\",\n  \"n\": 1\n}"
+	got := highlightJSON(&theme, data)
+
+	if plain := stripANSIString(got); plain != data {
+		t.Errorf("текст JSON изменился:\n%s\nожидалось\n%s", plain, data)
+	}
+	// Строка с экранированной кавычкой и двоеточием внутри - значение, а не ключ
+	if !strings.Contains(got, string(theme.JSONString)+`"a \"quoted\": text"`) {
+		t.Errorf("строка со спецсимволами подсвечена неверно: %q", got)
+	}
+	if !strings.Contains(got, string(theme.JSONKey)+`"msg"`) || !strings.Contains(got, string(theme.JSONNumber)+"1") {
+		t.Errorf("стили темы не применены: %q", got)
+	}
+}
+
+func TestHandle_JSONHighlighting(t *testing.T) {
+	type config struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	attrs := []slog.Attr{
+		slog.Any("cfg", config{Host: "db", Port: 5432}),
+		slog.Any("raw", json.RawMessage(`{"id":7}`)),
+	}
+
+	t.Run("Colors", func(t *testing.T) {
+		withColors(t)
+		h, buf := newTestHandler()
+		r := newTestRecord(slog.LevelInfo, "m")
+		r.AddAttrs(attrs...)
+		_ = h.Handle(context.Background(), r)
+
+		for _, want := range []string{
+			string(jsonKeyStyle) + `"host"`,
+			string(jsonNumberStyle) + "5432",
+			string(jsonKeyStyle) + `"id"`,
+		} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("нет %q в %q", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("NoColors", func(t *testing.T) {
+		h, buf := newTestHandler()
+		r := newTestRecord(slog.LevelInfo, "m")
+		r.AddAttrs(attrs...)
+		_ = h.Handle(context.Background(), r)
+
+		want := "[12:30:45] INF m cfg={\n  \"host\": \"db\",\n  \"port\": 5432\n} raw={\n  \"id\": 7\n}\n"
+		if buf.String() != want {
+			t.Errorf("вывод без цветов:\n%q\nожидалось\n%q", buf.String(), want)
+		}
+	})
+
+	t.Run("RuleDisablesHighlighting", func(t *testing.T) {
+		withColors(t)
+		h, buf := newTestHandler()
+		h.Rules = []Rule{{Key: "raw", ValueStyle: redStyle}}
+		r := newTestRecord(slog.LevelInfo, "m")
+		r.AddAttrs(attrs[1])
+		_ = h.Handle(context.Background(), r)
+
+		if !strings.Contains(buf.String(), string(redStyle)+"{\n  \"id\": 7\n}"+ansiReset) {
+			t.Errorf("значение с цветом из правила не должно подсвечиваться: %q", buf.String())
+		}
+	})
+}
+
+// stripANSIString удаляет SGR-последовательности
+func stripANSIString(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
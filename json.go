@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// appendJSONRecord собирает запись как JSON-объект с ключами slog.JSONHandler:
+// {"time":...,"level":"INFO","msg":"...",атрибуты}. Атрибуты из контекста
+// (ctxAttrs) идут сразу после msg, до групп из WithGroup.
+func (h *ColorHandler) appendJSONRecord(buf *buffer, r slog.Record, ctxAttrs []slog.Attr) {
+	buf.WriteByte('{')
+
+	if !r.Time.IsZero() {
+		appendJSONKey(buf, slog.TimeKey)
+		appendJSONTime(buf, r.Time)
+	}
+	appendJSONKey(buf, slog.LevelKey)
+	appendJSONString(buf, r.Level.String())
+	appendJSONKey(buf, slog.MessageKey)
+	appendJSONString(buf, r.Message)
+	h.processAttrs(buf, nil, ctxAttrs, false)
+
+	// Дописываем заранее отрисованные атрибуты (из WithAttrs)
+	buf.Write(h.preformatted)
+
+	opened := h.openGroups
+	if r.NumAttrs() > 0 {
+		// Неоткрытые группы из WithGroup откроются, только если
+		// в записи есть непустые атрибуты
+		attrs := buf
+		if opened < len(h.groups) {
+			attrs = newBuffer()
+			defer attrs.Free()
+		}
+		r.Attrs(func(attr slog.Attr) bool {
+			h.processAttr(attrs, nil, attr, false)
+			return true
+		})
+		if attrs != buf && appendJSONGroups(buf, h.groups[opened:], *attrs) {
+			opened = len(h.groups)
+		}
+	}
+
+	for range opened {
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('}')
+}
+
+// appendJSONGroups открывает groups и дописывает в них attrs - поля,
+// отрисованные в отдельном буфере (поэтому с запятой в начале). Как
+// и slog.JSONHandler, группы без полей не выводятся; результат сообщает,
+// были ли группы открыты.
+func appendJSONGroups(buf *buffer, groups []string, attrs []byte) bool {
+	if len(attrs) == 0 {
+		return false
+	}
+	for _, group := range groups {
+		appendJSONKey(buf, group)
+		buf.WriteByte('{')
+	}
+	if len(groups) > 0 {
+		attrs = attrs[1:] // первое поле в объекте идет без запятой
+	}
+	buf.Write(attrs)
+	return true
+}
+
+// appendJSONAttr выводит атрибут как поле JSON; группы становятся объектами
+func (h *ColorHandler) appendJSONAttr(buf *buffer, attr slog.Attr) {
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		// Группа без ключа встраивается в текущий объект
+		if attr.Key == "" {
+			h.processAttrs(buf, nil, attrs, false)
+			return
+		}
+		inner := newBuffer()
+		defer inner.Free()
+		h.processAttrs(inner, nil, attrs, false)
+		if appendJSONGroups(buf, []string{attr.Key}, *inner) {
+			buf.WriteByte('}')
+		}
+		return
+	}
+
+	appendJSONKey(buf, attr.Key)
+	h.appendJSONValue(buf, attr.Value)
+}
+
+// appendJSONKey дописывает разделитель (кроме начала объекта) и "key":
+func appendJSONKey(buf *buffer, key string) {
+	// Пустой буфер - поля, отрисованные отдельно (preformatted из WithAttrs,
+	// содержимое группы): лишнюю запятую в начале объекта убирает appendJSONGroups
+	if n := len(*buf); n == 0 || (*buf)[n-1] != '{' {
+		buf.WriteByte(',')
+	}
+	appendJSONString(buf, key)
+	buf.WriteByte(':')
+}
+
+// appendJSONValue дописывает значение атрибута в JSON.
+// Длительности, как и в slog.JSONHandler, выводятся в наносекундах.
+func (h *ColorHandler) appendJSONValue(buf *buffer, v slog.Value) {
+	switch v.Kind() {
+	case slog.KindString:
+		appendJSONString(buf, v.String())
+	case slog.KindInt64:
+		*buf = strconv.AppendInt(*buf, v.Int64(), 10)
+	case slog.KindUint64:
+		*buf = strconv.AppendUint(*buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		appendJSONFloat(buf, v.Float64())
+	case slog.KindBool:
+		*buf = strconv.AppendBool(*buf, v.Bool())
+	case slog.KindDuration:
+		*buf = strconv.AppendInt(*buf, int64(v.Duration()), 10)
+	case slog.KindTime:
+		appendJSONTime(buf, v.Time())
+	case slog.KindAny:
+		h.appendJSONAny(buf, v.Any())
+	default:
+		appendJSONString(buf, fmt.Sprint(v.Any()))
+	}
+}
+
+// appendJSONAny выводит произвольное значение через formatAnyValue:
+// JSON-строки и структуры встраиваются как JSON, остальное - строкой
+func (h *ColorHandler) appendJSONAny(buf *buffer, value any) {
+	if value == nil {
+		buf.WriteString("null")
+		return
+	}
+
+	switch v := formatAnyValue(value, h.jsonSniffLimit(), h.Formatters).(type) {
+	case error:
+		appendJSONString(buf, v.Error())
+	case formattedValue:
+		appendJSONString(buf, string(v))
+	case json.RawMessage:
+		appendJSONRaw(buf, v, string(v))
+	case string:
+		if _, ok := value.(string); ok {
+			appendJSONString(buf, v)
+			return
+		}
+		// Результат json.MarshalIndent: встраиваем в компактном виде
+		appendJSONRaw(buf, []byte(v), v)
+	default:
+		appendJSONString(buf, fmt.Sprintf("%+v", v))
+	}
+}
+
+// appendJSONRaw встраивает raw в компактном виде или, если это не JSON,
+// выводит fallback строкой
+func appendJSONRaw(buf *buffer, raw []byte, fallback string) {
+	dst := bytes.NewBuffer(*buf)
+	if err := json.Compact(dst, raw); err != nil {
+		*buf = dst.Bytes()[:len(*buf)]
+		appendJSONString(buf, fallback)
+		return
+	}
+	*buf = dst.Bytes()
+}
+
+// appendJSONTime выводит время в формате RFC3339 с наносекундами
+func appendJSONTime(buf *buffer, t time.Time) {
+	buf.WriteByte('"')
+	*buf = t.AppendFormat(*buf, time.RFC3339Nano)
+	buf.WriteByte('"')
+}
+
+// appendJSONFloat выводит число как encoding/json; NaN и ±Inf - строкой
+func appendJSONFloat(buf *buffer, f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, 64))
+		return
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	*buf = strconv.AppendFloat(*buf, f, format, -1, 64)
+
+	if format == 'e' {
+		// Как encoding/json: 1e-09 -> 1e-9
+		b := *buf
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			*buf = b[:n-1]
+		}
+	}
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString выводит s как строку JSON с экранированием
+// управляющих символов и заменой некорректного UTF-8 на U+FFFD
+func appendJSONString(buf *buffer, s string) {
+	buf.WriteByte('"')
+
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString("\ufffd")
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ──────────────────────────────────────────────────────────
+// Хелперы
+// ──────────────────────────────────────────────────────────
+
+// newJSONTestHandler создает handler в режиме FormatJSON с буфером
+func newJSONTestHandler() (*ColorHandler, *bytes.Buffer) {
+	h, buf := newTestHandler()
+	h.Format = FormatJSON
+	return h, buf
+}
+
+// ──────────────────────────────────────────────────────────
+// FormatJSON
+// ──────────────────────────────────────────────────────────
+
+func TestJSON_MatchesJSONHandler(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	record := func() slog.Record {
+		r := newTestRecord(slog.LevelWarn, "disk \"usage\"\n")
+		r.AddAttrs(
+			slog.String("mount", "/data"),
+			slog.Int("files", -3),
+			slog.Uint64("bytes", 1<<40),
+			slog.Float64("percent", 91.4),
+			slog.Float64("tiny", 1e-9),
+			slog.Bool("critical", true),
+			slog.Duration("elapsed", 1500*time.Millisecond),
+			slog.Time("checked", time.Date(2026, 2, 8, 12, 0, 0, 5, time.UTC)),
+			slog.Any("err", errors.New("no space left")),
+			slog.Any("point", point{1, 2}),
+			slog.Any("nothing", nil),
+			slog.Group("owner", slog.String("name", "Alice"), slog.Group("empty")),
+			slog.Group("", slog.String("inlined", "yes")),
+		)
+		return r
+	}
+
+	derive := []struct {
+		name string
+		fn   func(slog.Handler) slog.Handler
+	}{
+		{"plain", func(h slog.Handler) slog.Handler { return h }},
+		{"with_attrs", func(h slog.Handler) slog.Handler {
+			return h.WithAttrs([]slog.Attr{slog.String("service", "api")})
+		}},
+		{"groups", func(h slog.Handler) slog.Handler {
+			return h.WithAttrs([]slog.Attr{slog.Int("a", 1)}).
+				WithGroup("http").WithGroup("req").
+				WithAttrs([]slog.Attr{slog.String("method", "GET")}).
+				WithGroup("resp")
+		}},
+	}
+
+	for _, d := range derive {
+		t.Run(d.name, func(t *testing.T) {
+			h, got := newJSONTestHandler()
+			var want bytes.Buffer
+
+			_ = d.fn(h).Handle(context.Background(), record())
+			_ = d.fn(slog.NewJSONHandler(&want, nil)).Handle(context.Background(), record())
+
+			if got.String() != want.String() {
+				t.Errorf("вывод отличается от slog.JSONHandler\n got: %s\nwant: %s", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestJSON_EmptyGroupsOmitted(t *testing.T) {
+	h, buf := newJSONTestHandler()
+
+	// Группы без атрибутов не выводятся, как в slog.JSONHandler
+	_ = h.WithGroup("a").WithGroup("b").Handle(context.Background(), newTestRecord(slog.LevelInfo, "no attrs"))
+
+	want := `{"time":"2026-02-08T12:30:45Z","level":"INFO","msg":"no attrs"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("вывод = %s, ожидалось %s", buf.String(), want)
+	}
+}
+
+func TestJSON_GroupsOpenOnFirstAttr(t *testing.T) {
+	// Группа открывается, только когда в нее выводится непустой атрибут
+	tests := []struct {
+		name string
+		log  func(*slog.Logger)
+	}{
+		{"EmptyRecordAttr", func(l *slog.Logger) { l.WithGroup("g").Info("m", slog.Attr{}) }},
+		{"EmptyWithAttrs", func(l *slog.Logger) { l.WithGroup("g").With(slog.Attr{}).Info("m") }},
+		{"EmptyThenAttr", func(l *slog.Logger) { l.WithGroup("g").With(slog.Attr{}).Info("m", "a", 1) }},
+		{"EmptyInnerGroup", func(l *slog.Logger) {
+			l.WithGroup("g").With("x", 1).WithGroup("h").Info("m", slog.Group("e", slog.Attr{}))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, got := newJSONTestHandler()
+			var want bytes.Buffer
+			tt.log(slog.New(h))
+			tt.log(slog.New(slog.NewJSONHandler(&want, nil)))
+
+			// Время у записей разное: сравниваем с level
+			trim := func(s string) string { return s[strings.Index(s, `"level"`):] }
+			if trim(got.String()) != trim(want.String()) {
+				t.Errorf("вывод отличается от slog.JSONHandler\n got: %s\nwant: %s", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestJSON_EmbedsRawJSON(t *testing.T) {
+	h, buf := newJSONTestHandler()
+	r := newTestRecord(slog.LevelInfo, "webhook")
+	r.AddAttrs(slog.Any("payload", json.RawMessage(`{"action": "purchase", "items": ["book"]}`)))
+	_ = h.Handle(context.Background(), r)
+
+	var got struct {
+		Payload struct {
+			Action string   `json:"action"`
+			Items  []string `json:"items"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("некорректный JSON: %v (%s)", err, buf.String())
+	}
+	if got.Payload.Action != "purchase" || len(got.Payload.Items) != 1 {
+		t.Errorf("json.RawMessage не встроен как объект: %s", buf.String())
+	}
+}
+
+func TestJSON_SpecialValues(t *testing.T) {
+	h, buf := newJSONTestHandler()
+	r := newTestRecord(slog.LevelInfo, "special")
+	r.AddAttrs(
+		slog.Float64("nan", math.NaN()),
+		slog.String("bad_utf8", "a\xffb"),
+		slog.String("ctrl", "a\x01b"),
+	)
+	_ = h.Handle(context.Background(), r)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("некорректный JSON: %v (%s)", err, buf.String())
+	}
+	if got["nan"] != "NaN" || got["bad_utf8"] != "a�b" || got["ctrl"] != "a\x01b" {
+		t.Errorf("неверные значения: %v", got)
+	}
+}
+
+func TestJSON_NoColors(t *testing.T) {
+	withColors(t)
+	h, buf := newJSONTestHandler()
+	_ = h.WithAttrs([]slog.Attr{slog.String("k", "v")}).Handle(context.Background(), newTestRecord(slog.LevelError, "boom"))
+
+	if bytes.IndexByte(buf.Bytes(), '\x1b') >= 0 {
+		t.Errorf("JSON-вывод не должен содержать ANSI-последовательностей: %q", buf.String())
+	}
+}
+
+func TestJSON_HookCalled(t *testing.T) {
+	h, _ := newJSONTestHandler()
+	called := false
+	h.SetHook(func(ctx context.Context, r slog.Record) { called = true })
+
+	_ = h.Handle(context.Background(), newTestRecord(slog.LevelError, "boom"))
+	if !called {
+		t.Error("хук должен срабатывать и в режиме FormatJSON")
+	}
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// logfmtTimeFormat - формат времени slog.TextHandler (RFC3339 с миллисекундами)
+const logfmtTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// appendLogfmtRecord собирает запись в формате logfmt, совместимом
+// с slog.TextHandler: time=... level=INFO msg="..." group.key=value.
+// Атрибуты из контекста (ctxAttrs) выводятся последними, без префикса групп.
+func (h *ColorHandler) appendLogfmtRecord(buf *buffer, r slog.Record, ctxAttrs []slog.Attr) {
+	if !r.Time.IsZero() {
+		buf.WriteString(slog.TimeKey)
+		buf.WriteByte('=')
+		*buf = r.Time.AppendFormat(*buf, logfmtTimeFormat)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(slog.LevelKey)
+	buf.WriteByte('=')
+	buf.WriteString(r.Level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(slog.MessageKey)
+	buf.WriteByte('=')
+	appendLogfmtString(buf, r.Message)
+
+	// Дописываем заранее отрисованные атрибуты (из WithAttrs)
+	buf.Write(h.preformatted)
+
+	r.Attrs(func(attr slog.Attr) bool {
+		h.processAttr(buf, nil, attr, false)
+		return true
+	})
+
+	root := h
+	if len(h.groups) > 0 && len(ctxAttrs) > 0 {
+		root = h.clone()
+		root.groups = nil
+	}
+	root.processAttrs(buf, nil, ctxAttrs, false)
+}
+
+// appendLogfmtAttr выводит атрибут как key=value; ключ дополняется
+// группами из WithGroup и вложенных slog.Group через точку
+func (h *ColorHandler) appendLogfmtAttr(buf *buffer, attr slog.Attr) {
+	if attr.Value.Kind() == slog.KindGroup {
+		attrs := attr.Value.Group()
+		if attr.Key == "" {
+			h.processAttrs(buf, nil, attrs, false)
+			return
+		}
+		// Временный handler с добавленной группой для квалификации ключей
+		groupHandler := h.clone()
+		groupHandler.groups = append(h.groups[:len(h.groups):len(h.groups)], attr.Key)
+		groupHandler.processAttrs(buf, nil, attrs, false)
+		return
+	}
+
+	buf.WriteByte(' ')
+	appendLogfmtKey(buf, h.groups, attr.Key)
+	buf.WriteByte('=')
+	h.appendLogfmtValue(buf, attr.Value)
+}
+
+// appendLogfmtKey выводит ключ с префиксом групп, при необходимости в кавычках
+func appendLogfmtKey(buf *buffer, groups []string, key string) {
+	if len(groups) == 0 {
+		appendLogfmtString(buf, key)
+		return
+	}
+
+	tmp := newBuffer()
+	defer tmp.Free()
+	for _, g := range groups {
+		tmp.WriteString(g)
+		tmp.WriteByte('.')
+	}
+	tmp.WriteString(key)
+	appendLogfmtBytes(buf, *tmp)
+}
+
+// appendLogfmtValue дописывает значение атрибута в формате logfmt
+func (h *ColorHandler) appendLogfmtValue(buf *buffer, v slog.Value) {
+	switch v.Kind() {
+	case slog.KindString:
+		appendLogfmtString(buf, v.String())
+	case slog.KindInt64:
+		*buf = strconv.AppendInt(*buf, v.Int64(), 10)
+	case slog.KindUint64:
+		*buf = strconv.AppendUint(*buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		*buf = strconv.AppendFloat(*buf, v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		*buf = strconv.AppendBool(*buf, v.Bool())
+	case slog.KindDuration:
+		*buf = appendDuration(*buf, v.Duration())
+	case slog.KindTime:
+		*buf = v.Time().AppendFormat(*buf, logfmtTimeFormat)
+	case slog.KindAny:
+		h.appendLogfmtAny(buf, v.Any())
+	default:
+		appendLogfmtString(buf, fmt.Sprint(v.Any()))
+	}
+}
+
+// appendLogfmtAny выводит произвольное значение через formatAnyValue;
+// JSON (структуры и JSON-строки) выводится в компактном виде
+func (h *ColorHandler) appendLogfmtAny(buf *buffer, value any) {
+	// []byte, как в slog.TextHandler, выводится строкой в кавычках
+	if b, ok := value.([]byte); ok {
+		*buf = strconv.AppendQuote(*buf, string(b))
+		return
+	}
+
+	switch v := formatAnyValue(value, h.jsonSniffLimit(), h.Formatters).(type) {
+	case error:
+		appendLogfmtString(buf, v.Error())
+	case formattedValue:
+		appendLogfmtString(buf, string(v))
+	case json.RawMessage:
+		appendLogfmtJSON(buf, v)
+	case string:
+		if _, ok := value.(string); ok {
+			appendLogfmtString(buf, v)
+			return
+		}
+		appendLogfmtJSON(buf, []byte(v))
+	default:
+		appendLogfmtString(buf, fmt.Sprintf("%+v", v))
+	}
+}
+
+// appendLogfmtJSON сжимает JSON в одну строку и выводит его в кавычках.
+// Строка JSON выводится своим текстом, без второго слоя кавычек.
+func appendLogfmtJSON(buf *buffer, raw []byte) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		appendLogfmtBytes(buf, raw)
+		return
+	}
+	var s string
+	if compact.Len() > 0 && compact.Bytes()[0] == '"' && json.Unmarshal(compact.Bytes(), &s) == nil {
+		appendLogfmtString(buf, s)
+		return
+	}
+	appendLogfmtBytes(buf, compact.Bytes())
+}
+
+func appendLogfmtString(buf *buffer, s string) {
+	if needsQuoting(s) {
+		*buf = strconv.AppendQuote(*buf, s)
+		return
+	}
+	buf.WriteString(s)
+}
+
+func appendLogfmtBytes(buf *buffer, b []byte) {
+	if needsQuoting(string(b)) {
+		*buf = strconv.AppendQuote(*buf, string(b))
+		return
+	}
+	buf.Write(b)
+}
+
+// needsQuoting сообщает, нужно ли брать строку в кавычки: пустая строка,
+// пробелы, '=', '"', непечатаемые символы и некорректный UTF-8
+func needsQuoting(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b == '=' || b == '"' || b <= ' ' || b == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+		i += size
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newLogfmtTestHandler создает handler в режиме FormatLogfmt с буфером
+func newLogfmtTestHandler() (*ColorHandler, *bytes.Buffer) {
+	h, buf := newTestHandler()
+	h.Format = FormatLogfmt
+	return h, buf
+}
+
+// ──────────────────────────────────────────────────────────
+// FormatLogfmt
+// ──────────────────────────────────────────────────────────
+
+func TestLogfmt_MatchesTextHandler(t *testing.T) {
+	record := func() slog.Record {
+		r := newTestRecord(slog.LevelWarn, "disk usage high")
+		r.AddAttrs(
+			slog.String("mount", "/data"),
+			slog.String("label", "two words"),
+			slog.String("empty", ""),
+			slog.String("quote", `say "hi"`),
+			slog.String("eq", "a=b"),
+			slog.String("unicode", "привет"),
+			slog.Int("files", -3),
+			slog.Float64("percent", 91.4),
+			slog.Bool("critical", true),
+			slog.Duration("elapsed", 1500*time.Millisecond),
+			slog.Time("checked", time.Date(2026, 2, 8, 12, 0, 0, 5e6, time.UTC)),
+			slog.Any("err", errors.New("no space left")),
+			slog.Any("payload", []byte("hi")),
+			slog.Any("binary", []byte{0, 'a', 0xff}),
+			slog.Group("owner", slog.String("name", "Alice"), slog.Group("team", slog.Int("id", 7))),
+			slog.Group("", slog.String("inlined", "yes")),
+		)
+		return r
+	}
+
+	derive := []struct {
+		name string
+		fn   func(slog.Handler) slog.Handler
+	}{
+		{"plain", func(h slog.Handler) slog.Handler { return h }},
+		{"groups", func(h slog.Handler) slog.Handler {
+			return h.WithAttrs([]slog.Attr{slog.Int("a", 1)}).
+				WithGroup("http").
+				WithAttrs([]slog.Attr{slog.String("method", "GET"), slog.Group("g", slog.Int("x", 1))}).
+				WithGroup("resp")
+		}},
+	}
+
+	for _, d := range derive {
+		t.Run(d.name, func(t *testing.T) {
+			h, got := newLogfmtTestHandler()
+			var want bytes.Buffer
+
+			_ = d.fn(h).Handle(context.Background(), record())
+			_ = d.fn(slog.NewTextHandler(&want, nil)).Handle(context.Background(), record())
+
+			if got.String() != want.String() {
+				t.Errorf("вывод отличается от slog.TextHandler\n got: %s\nwant: %s", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestLogfmt_StructAsCompactJSON(t *testing.T) {
+	type cfg struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	h, buf := newLogfmtTestHandler()
+	r := newTestRecord(slog.LevelInfo, "loaded")
+	r.AddAttrs(slog.Any("cfg", cfg{Host: "localhost", Port: 5432}))
+	_ = h.Handle(context.Background(), r)
+
+	want := `time=2026-02-08T12:30:45.000Z level=INFO msg=loaded cfg="{\"host\":\"localhost\",\"port\":5432}"` + "\n"
+	if buf.String() != want {
+		t.Errorf("вывод = %s, ожидалось %s", buf.String(), want)
+	}
+}
+
+// switchState сериализуется в JSON строкой
+type switchState bool
+
+func (s switchState) MarshalJSON() ([]byte, error) {
+	if s {
+		return []byte(`"on"`), nil
+	}
+	return []byte(`"off duty"`), nil
+}
+
+func TestLogfmt_JSONStringNotDoubleQuoted(t *testing.T) {
+	h, buf := newLogfmtTestHandler()
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.Any("a", switchState(true)), slog.Any("b", switchState(false)))
+	_ = h.Handle(context.Background(), r)
+
+	if want := ` a=on b="off duty"` + "\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("вывод = %s, ожидалось окончание %s", buf.String(), want)
+	}
+}
+
+func TestLogfmt_NoColors(t *testing.T) {
+	withColors(t)
+	h, buf := newLogfmtTestHandler()
+	_ = h.WithGroup("g").Handle(context.Background(), newTestRecord(slog.LevelError, "boom"))
+
+	if bytes.IndexByte(buf.Bytes(), '\x1b') >= 0 {
+		t.Errorf("logfmt-вывод не должен содержать ANSI-последовательностей: %q", buf.String())
+	}
+}
+
+func TestNeedsQuoting(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"plain", false},
+		{"/api/users", false},
+		{"ünïcödé", false},
+		{"", true},
+		{"a b", true},
+		{"a=b", true},
+		{`a"b`, true},
+		{"a\tb", true},
+		{"a b", true},
+		{"a\xffb", true},
+	}
+
+	for _, tt := range tests {
+		if got := needsQuoting(tt.in); got != tt.want {
+			t.Errorf("needsQuoting(%q) = %v, ожидалось %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,29 +1,115 @@
 package logger
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/fatih/color"
 )
 
 func NewTestLogger() *slog.Logger {
 	return slog.New(NewColorHandler(os.Stdout))
 }
 
+// Format задает формат вывода ColorHandler
+type Format int
+
+const (
+	// FormatColor - цветная строка для терминала (по умолчанию)
+	FormatColor Format = iota
+	// FormatJSON - один JSON-объект на строку, без цветов
+	FormatJSON
+	// FormatLogfmt - key=value в стиле slog.TextHandler, без цветов
+	FormatLogfmt
+)
+
+// Ограничения проверки строк на JSON (ColorHandler.JSONSniffLimit)
+const (
+	// DefaultJSONSniffLimit - длина строки, до которой она проверяется на JSON
+	DefaultJSONSniffLimit = 64 << 10
+	// NoJSONSniffing отключает проверку: строки всегда выводятся как текст
+	NoJSONSniffing = -1
+
+	// DefaultHexDumpLimit - сколько байт []byte выводится в hex по умолчанию
+	DefaultHexDumpLimit = 256
+	// NoHexDump отключает hex: []byte выводится как JSON (base64)
+	NoHexDump = -1
+
+	// DefaultTableMaxRows - сколько строк таблицы выводится по умолчанию
+	DefaultTableMaxRows = 20
+	// DefaultTableColumnWidth - ширина колонки таблицы по умолчанию
+	DefaultTableColumnWidth = 24
+)
+
 // ColorHandler обрабатывает логи с цветовым форматированием
 type ColorHandler struct {
 	Writer io.Writer
 	HookFn func(ctx context.Context, r slog.Record)
+	// Format - формат вывода; задается до вызовов WithAttrs/WithGroup
+	Format Format
+	// Rules - правила выделения атрибутов в цветном выводе; применяется
+	// первое подходящее. Задаются до вызовов WithAttrs/WithGroup.
+	Rules []Rule
+	// Theme - цвета значений по типу; нулевой Theme выводит скаляры одним
+	// цветом, а JSON-значения - с подсветкой лексем цветами по умолчанию.
+	// Задается до вызовов WithAttrs/WithGroup.
+	Theme Theme
+	// HexDumpLimit - сколько байт []byte выводится в hex: короткие срезы -
+	// строкой в записи, длинные - дампом под ней.
+	// 0 - DefaultHexDumpLimit, NoHexDump выводит []byte как JSON (base64).
+	HexDumpLimit int
+	// Tables включает вывод однородных срезов структур (или map) и map
+	// структур выровненной таблицей под записью вместо JSON
+	Tables bool
+	// TableMaxRows - сколько строк таблицы выводится; 0 - DefaultTableMaxRows
+	TableMaxRows int
+	// TableColumnWidth - максимальная ширина колонки в символах, длинные
+	// значения обрезаются. 0 - DefaultTableColumnWidth.
+	TableColumnWidth int
+	// ContextExtractors извлекают атрибуты из контекста записи
+	// (InfoContext и т.п.); атрибуты из ContextWithAttrs добавляются всегда
+	ContextExtractors []ContextExtractor
+	// JSONSniffLimit - максимальная длина строки slog.Any в байтах, которую
+	// handler проверяет на JSON, чтобы вывести ее как встроенный JSON.
+	// 0 - DefaultJSONSniffLimit, NoJSONSniffing отключает проверку.
+	JSONSniffLimit int
+	// Formatters выводят значения slog.Any заданного типа или интерфейса
+	// текстом (NewFormatter) во всех форматах. Форматтер конкретного типа
+	// важнее интерфейсного, интерфейсы проверяются в порядке среза, и все
+	// они - раньше встроенных для fmt.Stringer и encoding.TextMarshaler.
+	// Задаются до вызовов WithAttrs/WithGroup.
+	Formatters []Formatter
+
 	groups []string    // текущие группы (в порядке добавления)
 	attrs  []slog.Attr // накопленные атрибуты
 
-	mu sync.Mutex
+	// preformatted - атрибуты из WithAttrs, отрисованные один раз
+	// и дописываемые в каждую запись без повторного форматирования.
+	// Цветной формат отрисовывает их и без цветов, и с цветами
+	// (preformattedColored): запись целиком следует color.NoColor.
+	preformatted, preformattedColored []byte
+	// preformattedBlocks - блоки под строкой записи (hex-дампы) для тех же атрибутов
+	preformattedBlocks, preformattedColoredBlocks []byte
+	// openGroups - сколько групп из groups уже открыто в preformatted (JSON)
+	openGroups int
+
+	core *handlerCore // общее с производными handler'ами состояние
+}
+
+// handlerCore - состояние, общее для handler'а и всех созданных из него
+// через WithAttrs/WithGroup: они пишут в один Writer и закрываются вместе
+type handlerCore struct {
+	mu     sync.Mutex
+	closed atomic.Bool
 }
 
 // NewColorHandler создает новый ColorHandler
@@ -32,160 +118,498 @@ func NewColorHandler(w io.Writer) *ColorHandler {
 		Writer: w,
 		groups: []string{},
 		attrs:  []slog.Attr{},
+		core:   &handlerCore{},
 	}
 }
 
 // WithGroup реализует slog.HandlerWithGroup
 func (h *ColorHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
 	// Создаем новый handler с добавленной группой
-	newHandler := &ColorHandler{
-		Writer: h.Writer,
-		HookFn: h.HookFn,
-		groups: make([]string, len(h.groups)),
-		attrs:  h.attrs, // разделяем атрибуты
-	}
-	copy(newHandler.groups, h.groups)
-	newHandler.groups = append(newHandler.groups, name)
+	newHandler := h.clone()
+	newHandler.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
 	return newHandler
 }
 
 // WithAttrs реализует slog.HandlerWithAttrs
 func (h *ColorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
 	// Создаем новый handler с добавленными атрибутами
-	newHandler := &ColorHandler{
-		Writer: h.Writer,
-		HookFn: h.HookFn,
-		groups: h.groups, // разделяем группы
-		attrs:  append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
+	newHandler := h.clone()
+	newHandler.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+
+	// Отрисовываем новые атрибуты сразу, чтобы Handle только копировал байты
+	buf, blocks := newBuffer(), newBuffer()
+	defer buf.Free()
+	defer blocks.Free()
+	switch h.Format {
+	case FormatJSON:
+		// Группы из WithGroup открываются, только когда в них появляются атрибуты
+		fields := newBuffer()
+		defer fields.Free()
+		h.processAttrs(fields, nil, attrs, false)
+		if appendJSONGroups(buf, h.groups[h.openGroups:], *fields) {
+			newHandler.openGroups = len(h.groups)
+		}
+	case FormatLogfmt:
+		h.processAttrs(buf, nil, attrs, false)
+	default:
+		h.processAttrs(buf, blocks, attrs, false)
+
+		colored, coloredBlocks := newBuffer(), newBuffer()
+		defer colored.Free()
+		defer coloredBlocks.Free()
+		h.processAttrs(colored, coloredBlocks, attrs, true)
+		newHandler.preformattedColored = extend(h.preformattedColored, *colored)
+		newHandler.preformattedColoredBlocks = extend(h.preformattedColoredBlocks, *coloredBlocks)
 	}
+	newHandler.preformatted = extend(h.preformatted, *buf)
+	newHandler.preformattedBlocks = extend(h.preformattedBlocks, *blocks)
 	return newHandler
 }
 
-// Enabled всегда возвращает true (логируем все уровни)
+// extend возвращает prev с дописанным add, не изменяя prev: его массив
+// общий с родительским handler'ом
+func extend(prev, add []byte) []byte {
+	if len(add) == 0 {
+		return prev
+	}
+	return append(prev[:len(prev):len(prev)], add...)
+}
+
+// clone возвращает копию handler'а с общими (неизменяемыми) срезами
+func (h *ColorHandler) clone() *ColorHandler {
+	return &ColorHandler{
+		Writer:             h.Writer,
+		HookFn:             h.HookFn,
+		Format:             h.Format,
+		Rules:              h.Rules,
+		Theme:              h.Theme,
+		HexDumpLimit:       h.HexDumpLimit,
+		Tables:             h.Tables,
+		TableMaxRows:       h.TableMaxRows,
+		TableColumnWidth:   h.TableColumnWidth,
+		ContextExtractors:  h.ContextExtractors,
+		JSONSniffLimit:     h.JSONSniffLimit,
+		Formatters:         h.Formatters,
+		groups:             h.groups,
+		attrs:              h.attrs,
+		preformatted:       h.preformatted,
+		preformattedBlocks: h.preformattedBlocks,
+		openGroups:         h.openGroups,
+		core:               h.core,
+
+		preformattedColored:       h.preformattedColored,
+		preformattedColoredBlocks: h.preformattedColoredBlocks,
+	}
+}
+
+// jsonSniffLimit возвращает действующий предел для isJSON
+func (h *ColorHandler) jsonSniffLimit() int {
+	if h.JSONSniffLimit == 0 {
+		return DefaultJSONSniffLimit
+	}
+	return h.JSONSniffLimit
+}
+
+// Enabled возвращает true для всех уровней, пока handler не закрыт
 func (h *ColorHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return true
+	return !h.core.closed.Load()
 }
 
 // Handle - применяет цвета и форматирует запись с поддержкой групп
 func (h *ColorHandler) Handle(ctx context.Context, r slog.Record) error {
+	// После Close запись - no-op: хук не вызывается, запись не форматируется
+	if h.core.closed.Load() {
+		return ErrClosed
+	}
 
 	buf := newBuffer()
 	defer buf.Free()
 
+	// Атрибуты из контекста видны и хуку, и всем форматам вывода;
+	// форматы выводят их вне групп из WithGroup
+	ctxAttrs := h.contextAttrs(ctx)
+
 	// Вызываем хук ДО обработки основным handler'ом
 	if h.HookFn != nil && r.Level >= slog.LevelError {
-		h.HookFn(ctx, r)
+		h.HookFn(ctx, withAttrs(r, ctxAttrs))
 	}
 
-	// Формируем временную метку
-	timeStr := r.Time.Format(time.TimeOnly)
-
-	// Выбираем цвет в зависимости от уровня логирования
-	var levelColor *color.Color
-	var msgColor *color.Color // цвет сообщения
-	var levelStr string
-
-	switch r.Level {
-	case slog.LevelDebug:
-		levelColor = color.New(color.FgHiCyan)
-		msgColor = color.New(color.FgHiCyan) // подсвечиваем сообщение Debug
-		levelStr = "DBG"
-	case slog.LevelInfo:
-		levelColor = color.New(color.FgGreen)
-		msgColor = color.New(color.FgGreen) // подсвечиваем сообщение Info
-		levelStr = "INF"
-	case slog.LevelWarn:
-		levelColor = color.New(color.FgHiYellow)
-		msgColor = color.New(color.FgHiWhite)
-		levelStr = "WRN"
-	case slog.LevelError:
-		levelColor = color.New(color.FgHiRed)
-		msgColor = color.New(color.FgHiWhite)
-		levelStr = "ERR"
+	switch h.Format {
+	case FormatJSON:
+		h.appendJSONRecord(buf, r, ctxAttrs)
+	case FormatLogfmt:
+		h.appendLogfmtRecord(buf, r, ctxAttrs)
 	default:
-		levelColor = color.New(color.FgWhite)
-		msgColor = color.New(color.FgHiWhite)
-		levelStr = "???"
+		h.appendColorRecord(buf, r, ctxAttrs, treePosOf(ctx))
 	}
 
-	// Собираем красивую строку
-	_, err := color.New(color.FgHiBlue).Fprintf(buf, "[%s] ", timeStr)
-	if err != nil {
-		return err
+	buf.WriteByte('\n')
+
+	h.core.mu.Lock()
+	defer h.core.mu.Unlock()
+
+	// Close мог случиться, пока запись форматировалась
+	if h.core.closed.Load() {
+		return ErrClosed
 	}
-	_, err = levelColor.Fprintf(buf, "%-3s ", levelStr)
-	if err != nil {
-		return err
+
+	_, err := h.Writer.Write(*buf)
+	return err
+}
+
+// Flush сбрасывает буферы Writer'а, если он поддерживает Flush() или Sync()
+func (h *ColorHandler) Flush() error {
+	h.core.mu.Lock()
+	defer h.core.mu.Unlock()
+
+	// После Close writer уже закрыт: Flush возвращает ErrClosed
+	if h.core.closed.Load() {
+		return ErrClosed
 	}
+	return flushWriter(h.Writer)
+}
 
-	// Выводим группы в правильном порядке (слева направо)
-	if len(h.groups) > 0 {
-		for _, group := range h.groups {
-			color.New(color.FgHiBlue).Fprintf(buf, "%s.", group)
+// Close сбрасывает и закрывает Writer (кроме os.Stdout и os.Stderr).
+// Закрываются также все производные handler'ы: после Close Enabled
+// возвращает false, а Handle - ErrClosed. Повторный вызов ничего не делает.
+func (h *ColorHandler) Close() error {
+	h.core.mu.Lock()
+	defer h.core.mu.Unlock()
+
+	if h.core.closed.Swap(true) {
+		return nil
+	}
+	return errors.Join(flushWriter(h.Writer), closeWriter(h.Writer))
+}
+
+// flushWriter сбрасывает буферы w, если он это поддерживает
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Sync() error }:
+		// Sync для терминала возвращает EINVAL, стандартные потоки пропускаем
+		if isStdStream(w) {
+			return nil
 		}
+		return f.Sync()
 	}
+	return nil
+}
 
-	_, err = msgColor.Fprintf(buf, "%s", r.Message)
-	if err != nil {
-		return err
+// closeWriter закрывает w, если это io.Closer и не стандартный поток
+func closeWriter(w io.Writer) error {
+	if c, ok := w.(io.Closer); ok && !isStdStream(w) {
+		return c.Close()
+	}
+	return nil
+}
+
+func isStdStream(w io.Writer) bool {
+	return w == os.Stdout || w == os.Stderr
+}
+
+// appendColorRecord собирает цветную строку; перед сообщением вложенных
+// операций (Nest, Span) выводятся линии дерева, атрибуты из контекста
+// (ctxAttrs) - последними:
+// [время] уровень группы.сообщение атрибуты
+func (h *ColorHandler) appendColorRecord(buf *buffer, r slog.Record, ctxAttrs []slog.Attr, pos treePos) {
+	// Режим цвета определяется один раз на запись
+	colored := colorsEnabled()
+
+	// Выбираем цвет в зависимости от уровня логирования
+	lf := formatForLevel(r.Level)
+
+	buf.setStyle(timeStyle, colored)
+	buf.WriteByte('[')
+	*buf = r.Time.AppendFormat(*buf, time.TimeOnly)
+	buf.WriteString("] ")
+	buf.resetStyle(colored)
+
+	buf.setStyle(lf.level, colored)
+	buf.WriteString(lf.label)
+	buf.WriteByte(' ')
+	buf.resetStyle(colored)
+
+	appendTreeGuides(buf, pos, colored)
+
+	// Выводим группы в правильном порядке (слева направо)
+	for _, group := range h.groups {
+		buf.setStyle(groupStyle, colored)
+		buf.WriteString(group)
+		buf.WriteByte('.')
+		buf.resetStyle(colored)
+	}
+
+	buf.setStyle(lf.msg, colored)
+	buf.WriteString(r.Message)
+	buf.resetStyle(colored)
+
+	// Дописываем заранее отрисованные атрибуты (из WithAttrs) в режиме записи
+	preformatted, preformattedBlocks := h.preformatted, h.preformattedBlocks
+	if colored {
+		preformatted, preformattedBlocks = h.preformattedColored, h.preformattedColoredBlocks
 	}
+	buf.Write(preformatted)
 
-	// Обрабатываем предварительно накопленные атрибуты (из WithAttrs)
-	h.processAttrs(buf, h.attrs)
+	// Блоки (hex-дампы) собираются отдельно и выводятся под строкой
+	blocks := newBuffer()
+	defer blocks.Free()
+	blocks.Write(preformattedBlocks)
 
 	// Обрабатываем атрибуты из записи
 	r.Attrs(func(attr slog.Attr) bool {
-		h.processAttr(buf, attr)
+		h.processAttr(buf, blocks, attr, colored)
 		return true
 	})
+	h.processAttrs(buf, blocks, ctxAttrs, colored)
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	_, err = io.WriteString(buf, "\n")
-	_, err = h.Writer.Write(*buf)
-
-	return err
+	buf.Write(*blocks)
 }
 
 // processAttrs обрабатывает массив атрибутов
-func (h *ColorHandler) processAttrs(buf *buffer, attrs []slog.Attr) {
+func (h *ColorHandler) processAttrs(buf, blocks *buffer, attrs []slog.Attr, colored bool) {
 	for _, attr := range attrs {
-		h.processAttr(buf, attr)
+		h.processAttr(buf, blocks, attr, colored)
+	}
+}
+
+// processAttr обрабатывает один атрибут с учетом групп. В blocks цветной
+// формат дописывает многострочные блоки, которые выводятся под строкой
+// записи; остальные форматы передают nil. colored - режим цвета записи,
+// JSON и logfmt его не используют.
+func (h *ColorHandler) processAttr(buf, blocks *buffer, attr slog.Attr, colored bool) {
+	attr.Value = attr.Value.Resolve()
+
+	// Длительность спана раскрашивается только в цветном формате
+	if d, ok := spanElapsedOf(attr.Value); ok && h.Format != FormatColor {
+		attr.Value = slog.DurationValue(d)
+	}
+
+	// Пустые атрибуты игнорируются (соглашение slog.Handler)
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	switch h.Format {
+	case FormatJSON:
+		h.appendJSONAttr(buf, attr)
+	case FormatLogfmt:
+		h.appendLogfmtAttr(buf, attr)
+	default:
+		h.appendColorAttr(buf, blocks, attr, colored)
 	}
 }
 
-// processAttr обрабатывает один атрибут с учетом групп
-func (h *ColorHandler) processAttr(buf *buffer, attr slog.Attr) {
-	// Обрабатываем вложенные группы
+// appendColorAttr выводит атрибут как цветную пару ключ=значение
+func (h *ColorHandler) appendColorAttr(buf, blocks *buffer, attr slog.Attr, colored bool) {
+	// Обрабатываем вложенные группы: ключи выводятся без префикса группы
 	if attr.Value.Kind() == slog.KindGroup {
-		groupAttrs := attr.Value.Group()
-		// Создаем временный handler для обработки группы
-		groupHandler := &ColorHandler{
-			Writer: buf,
-			groups: append(h.groups[:len(h.groups):len(h.groups)], attr.Key),
-		}
-		for _, groupAttr := range groupAttrs {
-			groupHandler.processAttr(buf, groupAttr)
-		}
+		h.processAttrs(buf, blocks, attr.Value.Group(), colored)
 		return
 	}
 
-	// Выводим группы перед ключом (в правильном порядке)
-	// if len(h.groups) > 0 {
-	// 	color.New(color.FgHiBlue).Fprintf(h.Writer, " ")
-	// 	for _, group := range h.groups {
-	// 		color.New(color.FgHiBlue).Fprintf(h.Writer, "%s.", group)
-	// 	}
-	// }
+	// Лексемы JSON подсвечиваются, если цвет значения не задан правилом
+	ks, vs := keyStyle, valueStyle
+	highlight := colored
+	shortID, isShortID := shortIDOf(attr.Value)
+	if colored {
+		vs = cmp.Or(h.Theme.valueStyle(attr.Value, h.Formatters), vs)
+		if isShortID {
+			// Идентификаторы нужны для поиска, а не для чтения
+			ks, vs = idStyle, idStyle
+		}
+		if d, ok := spanElapsedOf(attr.Value); ok {
+			vs = elapsedStyle(d)
+		}
+		if rule := h.ruleFor(attr); rule != nil {
+			ks = cmp.Or(rule.KeyStyle, ks)
+			vs = cmp.Or(rule.ValueStyle, vs)
+			highlight = rule.ValueStyle == ""
+		}
+	}
 
 	// Выводим ключ и значение
-	color.New(color.FgHiGreen).Fprintf(buf, " %s=", attr.Key)
-	color.New(color.FgHiYellow).Fprintf(buf, "%v", formatValue(attr.Value))
+	buf.setStyle(ks, colored)
+	buf.WriteByte(' ')
+	buf.WriteString(attr.Key)
+	buf.WriteByte('=')
+	buf.resetStyle(colored)
+
+	buf.setStyle(vs, colored)
+	if isShortID {
+		buf.WriteString(shortID)
+	} else if d, ok := spanElapsedOf(attr.Value); ok {
+		*buf = appendDuration(*buf, d)
+	} else if data, ok := h.hexBytes(attr.Value); ok {
+		h.appendHex(buf, blocks, attr.Key, data, vs, colored)
+	} else if d, ok := diffOf(attr.Value); ok && blocks != nil {
+		appendDiff(buf, blocks, attr.Key, d, colored)
+	} else if t, ok := h.tableOf(attr.Value); ok && blocks != nil {
+		h.appendTable(buf, blocks, attr.Key, t, vs, colored)
+	} else if highlight {
+		h.appendColorValue(buf, attr.Value, vs)
+	} else {
+		h.appendValue(buf, attr.Value)
+	}
+	buf.resetStyle(colored)
+}
+
+// appendValue дописывает значение атрибута без промежуточных аллокаций
+// для скалярных типов; остальные проходят через formatValue.
+func (h *ColorHandler) appendValue(buf *buffer, v slog.Value) {
+	switch v.Kind() {
+	case slog.KindString:
+		buf.WriteString(v.String())
+	case slog.KindInt64:
+		*buf = strconv.AppendInt(*buf, v.Int64(), 10)
+	case slog.KindUint64:
+		*buf = strconv.AppendUint(*buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		*buf = strconv.AppendFloat(*buf, v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		*buf = strconv.AppendBool(*buf, v.Bool())
+	case slog.KindDuration:
+		*buf = appendDuration(*buf, v.Duration())
+	case slog.KindTime:
+		*buf = v.Time().AppendFormat(*buf, time.RFC3339)
+	default:
+		appendAny(buf, formatValue(v, h.jsonSniffLimit(), h.Formatters))
+	}
+}
+
+// appendDuration дописывает d в формате time.Duration.String без аллокаций
+func appendDuration(dst []byte, d time.Duration) []byte {
+	// Самый длинный вариант: "-2562047h47m16.854775808s"
+	var arr [32]byte
+	w := len(arr)
+
+	u := uint64(d)
+	neg := d < 0
+	if neg {
+		u = -u
+	}
+
+	if u < uint64(time.Second) {
+		// Меньше секунды: единицы помельче, например "1.2ms"
+		var prec int
+		w--
+		arr[w] = 's'
+		w--
+		switch {
+		case u == 0:
+			return append(dst, "0s"...)
+		case u < uint64(time.Microsecond):
+			prec = 0
+			arr[w] = 'n'
+		case u < uint64(time.Millisecond):
+			prec = 3
+			// U+00B5 'µ' в UTF-8 занимает два байта
+			w--
+			copy(arr[w:], "µ")
+		default:
+			prec = 6
+			arr[w] = 'm'
+		}
+		w, u = fmtFrac(arr[:w], u, prec)
+		w = fmtInt(arr[:w], u)
+	} else {
+		w--
+		arr[w] = 's'
+
+		w, u = fmtFrac(arr[:w], u, 9)
+
+		// u - целые секунды
+		w = fmtInt(arr[:w], u%60)
+		u /= 60
+
+		// u - целые минуты
+		if u > 0 {
+			w--
+			arr[w] = 'm'
+			w = fmtInt(arr[:w], u%60)
+			u /= 60
+
+			// u - целые часы
+			if u > 0 {
+				w--
+				arr[w] = 'h'
+				w = fmtInt(arr[:w], u)
+			}
+		}
+	}
+
+	if neg {
+		w--
+		arr[w] = '-'
+	}
+	return append(dst, arr[w:]...)
 }
 
-// formatValue форматирует значение атрибута
-func formatValue(v slog.Value) interface{} {
+// fmtFrac записывает дробную часть v/10^prec в конец buf, опуская
+// хвостовые нули и точку, если дробная часть нулевая
+func fmtFrac(buf []byte, v uint64, prec int) (nw int, nv uint64) {
+	w := len(buf)
+	printed := false
+	for i := 0; i < prec; i++ {
+		digit := v % 10
+		printed = printed || digit != 0
+		if printed {
+			w--
+			buf[w] = byte(digit) + '0'
+		}
+		v /= 10
+	}
+	if printed {
+		w--
+		buf[w] = '.'
+	}
+	return w, v
+}
+
+// fmtInt записывает v в конец buf и возвращает индекс начала
+func fmtInt(buf []byte, v uint64) int {
+	w := len(buf)
+	if v == 0 {
+		w--
+		buf[w] = '0'
+	} else {
+		for v > 0 {
+			w--
+			buf[w] = byte(v%10) + '0'
+			v /= 10
+		}
+	}
+	return w
+}
+
+// appendAny дописывает результат formatValue/formatAnyValue
+func appendAny(buf *buffer, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		buf.WriteString(v)
+	case formattedValue:
+		buf.WriteString(string(v))
+	case json.RawMessage:
+		buf.Write(v)
+	case error:
+		buf.WriteString(v.Error())
+	default:
+		*buf = fmt.Append(*buf, v)
+	}
+}
+
+// formatValue форматирует значение атрибута; sniffLimit - см. isJSON,
+// fs - форматтеры handler'а
+func formatValue(v slog.Value, sniffLimit int, fs []Formatter) interface{} {
 	switch v.Kind() {
 	case slog.KindString:
 		return v.String()
@@ -202,13 +626,13 @@ func formatValue(v slog.Value) interface{} {
 	case slog.KindTime:
 		return v.Time().Format(time.RFC3339)
 	case slog.KindAny:
-		return formatAnyValue(v.Any())
+		return formatAnyValue(v.Any(), sniffLimit, fs)
 	default:
 		return v.Any()
 	}
 }
 
-func formatAnyValue(value interface{}) interface{} {
+func formatAnyValue(value interface{}, sniffLimit int, fs []Formatter) interface{} {
 	// Если значение уже является JSON-строкой, возвращаем как есть
 
 	switch v := value.(type) {
@@ -216,8 +640,13 @@ func formatAnyValue(value interface{}) interface{} {
 		return v
 	}
 
+	// Форматтеры handler'а, затем fmt.Stringer и encoding.TextMarshaler
+	if s, ok := formatWith(fs, value); ok {
+		return s
+	}
+
 	if str, ok := value.(string); ok {
-		if isJSON(str) {
+		if isJSON(str, sniffLimit) {
 			return json.RawMessage(str)
 		}
 		return str
@@ -232,9 +661,19 @@ func formatAnyValue(value interface{}) interface{} {
 	return string(jsonBytes)
 }
 
-func isJSON(str string) bool {
-	var js json.RawMessage
-	return json.Unmarshal([]byte(str), &js) == nil
+// isJSON сообщает, содержит ли строка JSON-объект или массив. Дешевая
+// проверка первого символа отсекает обычный текст, числа, true и null
+// до json.Valid; строки длиннее limit не проверяются, limit <= 0
+// отключает распознавание.
+func isJSON(str string, limit int) bool {
+	if len(str) > limit {
+		return false
+	}
+	s := strings.TrimLeft(str, " \t\r\n")
+	if s == "" || s[0] != '{' && s[0] != '[' {
+		return false
+	}
+	return json.Valid([]byte(s))
 }
 
 // SetHook устанавливает функцию хука для ошибок
@@ -215,6 +215,62 @@ func TestWithAttrs_DoesNotMutateOriginal(t *testing.T) {
 	}
 }
 
+func TestWithAttrs_Preformatted(t *testing.T) {
+	h, _ := newTestHandler()
+	h2 := h.WithAttrs([]slog.Attr{slog.String("service", "api")}).(*ColorHandler)
+
+	if got := string(h2.preformatted); got != " service=api" {
+		t.Errorf("preformatted = %q, ожидалось %q", got, " service=api")
+	}
+	if len(h.preformatted) != 0 {
+		t.Error("WithAttrs изменил preformatted оригинального handler'а")
+	}
+}
+
+func TestWithAttrs_Chained(t *testing.T) {
+	h, buf := newTestHandler()
+	base := h.WithAttrs([]slog.Attr{slog.String("a", "1")})
+
+	// Два потомка одного handler'а не должны затирать атрибуты друг друга
+	left := base.WithAttrs([]slog.Attr{slog.String("b", "2")})
+	right := base.WithGroup("g").WithAttrs([]slog.Attr{slog.String("c", "3")})
+
+	_ = left.Handle(context.Background(), newTestRecord(slog.LevelInfo, "left"))
+	_ = right.Handle(context.Background(), newTestRecord(slog.LevelInfo, "right"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ожидалось 2 строки, получено %d: %q", len(lines), buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "left a=1 b=2") {
+		t.Errorf("неверная строка left: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "g.right a=1 c=3") {
+		t.Errorf("неверная строка right: %q", lines[1])
+	}
+}
+
+func TestWithAttrs_FollowsColorMode(t *testing.T) {
+	h, buf := newTestHandler()
+	h2 := h.WithAttrs([]slog.Attr{slog.String("a", "1")})
+	colored := string(keyStyle) + " a=" + ansiReset + string(valueStyle) + "1" + ansiReset
+
+	// Цвета включены после WithAttrs: атрибуты из With тоже цветные
+	withColors(t)
+	_ = h2.Handle(context.Background(), newTestRecord(slog.LevelInfo, "m"))
+	if !strings.Contains(buf.String(), colored) {
+		t.Errorf("атрибуты из WithAttrs выведены без цветов: %q", buf.String())
+	}
+
+	// И выключаются вместе со всей строкой
+	color.NoColor = true
+	buf.Reset()
+	_ = h2.Handle(context.Background(), newTestRecord(slog.LevelInfo, "m"))
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("строка смешивает цветные и простые части: %q", buf.String())
+	}
+}
+
 // ──────────────────────────────────────────────────────────
 // WithGroup
 // ──────────────────────────────────────────────────────────
@@ -312,6 +368,96 @@ func TestSetHook_Nil(t *testing.T) {
 	}
 }
 
+// ──────────────────────────────────────────────────────────
+// Flush / Close
+// ──────────────────────────────────────────────────────────
+
+// lifecycleWriter запоминает вызовы Flush и Close
+type lifecycleWriter struct {
+	bytes.Buffer
+	flushes int
+	closes  int
+}
+
+func (w *lifecycleWriter) Flush() error {
+	w.flushes++
+	return nil
+}
+
+func (w *lifecycleWriter) Close() error {
+	w.closes++
+	return nil
+}
+
+func TestFlush_PropagatesToWriter(t *testing.T) {
+	w := &lifecycleWriter{}
+	h := NewColorHandler(w)
+
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush вернул ошибку: %v", err)
+	}
+	if w.flushes != 1 {
+		t.Errorf("Flush writer'а вызван %d раз, ожидалось 1", w.flushes)
+	}
+}
+
+func TestClose_PropagatesToWriter(t *testing.T) {
+	w := &lifecycleWriter{}
+	h := NewColorHandler(w)
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("повторный Close вернул ошибку: %v", err)
+	}
+	if w.flushes != 1 || w.closes != 1 {
+		t.Errorf("flushes=%d closes=%d, ожидалось по одному вызову", w.flushes, w.closes)
+	}
+}
+
+func TestClose_DisablesDerivedHandlers(t *testing.T) {
+	w := &lifecycleWriter{}
+	h := NewColorHandler(w)
+	derived := h.WithAttrs([]slog.Attr{slog.String("k", "v")}).WithGroup("g")
+
+	_ = derived.(*ColorHandler).Close()
+
+	for name, hh := range map[string]slog.Handler{"original": h, "derived": derived} {
+		if hh.Enabled(context.Background(), slog.LevelError) {
+			t.Errorf("%s: Enabled после Close должен возвращать false", name)
+		}
+		err := hh.Handle(context.Background(), newTestRecord(slog.LevelInfo, "late"))
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("%s: Handle после Close = %v, ожидалось ErrClosed", name, err)
+		}
+	}
+	if err := h.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush после Close = %v, ожидалось ErrClosed", err)
+	}
+
+	// slog.Logger не вызывает Handle для выключенного handler'а: запись - no-op
+	slog.New(h).Info("ignored")
+	if w.Len() != 0 {
+		t.Errorf("после Close ничего не должно записываться: %q", w.String())
+	}
+}
+
+func TestClose_HandleSkipsHook(t *testing.T) {
+	h, _ := newTestHandler()
+	hooked := false
+	h.SetHook(func(context.Context, slog.Record) { hooked = true })
+	_ = h.Close()
+
+	err := h.Handle(context.Background(), newTestRecord(slog.LevelError, "late"))
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Handle после Close = %v, ожидалось ErrClosed", err)
+	}
+	if hooked {
+		t.Error("после Close хук не должен вызываться")
+	}
+}
+
 // ──────────────────────────────────────────────────────────
 // formatValue
 // ──────────────────────────────────────────────────────────
@@ -336,7 +482,7 @@ func TestFormatValue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := fmt.Sprintf("%v", formatValue(tt.val))
+			got := fmt.Sprintf("%v", formatValue(tt.val, DefaultJSONSniffLimit, nil))
 			if got != tt.want {
 				t.Errorf("formatValue(%v) = %q, ожидалось %q", tt.val, got, tt.want)
 			}
@@ -350,7 +496,7 @@ func TestFormatValue(t *testing.T) {
 
 func TestFormatAnyValue_Error(t *testing.T) {
 	err := errors.New("test error")
-	got := formatAnyValue(err)
+	got := formatAnyValue(err, DefaultJSONSniffLimit, nil)
 	if gotErr, ok := got.(error); !ok || gotErr.Error() != "test error" {
 		t.Errorf("formatAnyValue(error) = %v, ожидалось error 'test error'", got)
 	}
@@ -358,7 +504,7 @@ func TestFormatAnyValue_Error(t *testing.T) {
 
 func TestFormatAnyValue_JSONString(t *testing.T) {
 	jsonStr := `{"key":"value"}`
-	got := formatAnyValue(jsonStr)
+	got := formatAnyValue(jsonStr, DefaultJSONSniffLimit, nil)
 	if _, ok := got.(json.RawMessage); !ok {
 		t.Errorf("formatAnyValue(JSON-строка) должен вернуть json.RawMessage, получил %T", got)
 	}
@@ -366,7 +512,7 @@ func TestFormatAnyValue_JSONString(t *testing.T) {
 
 func TestFormatAnyValue_PlainString(t *testing.T) {
 	s := "plain text"
-	got := formatAnyValue(s)
+	got := formatAnyValue(s, DefaultJSONSniffLimit, nil)
 	if gotStr, ok := got.(string); !ok || gotStr != s {
 		t.Errorf("formatAnyValue(обычная строка) = %v, ожидалось %q", got, s)
 	}
@@ -378,7 +524,7 @@ func TestFormatAnyValue_Struct(t *testing.T) {
 		Age  int    `json:"age"`
 	}
 	d := data{Name: "Bob", Age: 30}
-	got := formatAnyValue(d)
+	got := formatAnyValue(d, DefaultJSONSniffLimit, nil)
 	gotStr, ok := got.(string)
 	if !ok {
 		t.Fatalf("formatAnyValue(struct) вернул %T, ожидалось string (JSON)", got)
@@ -399,22 +545,59 @@ func TestIsJSON(t *testing.T) {
 	}{
 		{`{"a":1}`, true},
 		{`[1,2,3]`, true},
-		{`"hello"`, true},
-		{`42`, true},
+		{" \n\t{\"a\": [1]}", true},
+		{`"hello"`, false},
+		{`42`, false},
+		{`true`, false},
+		{`null`, false},
 		{`not json`, false},
 		{`{broken`, false},
+		{`[1,2] trailing`, false},
 		{``, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			if got := isJSON(tt.input); got != tt.want {
+			if got := isJSON(tt.input, DefaultJSONSniffLimit); got != tt.want {
 				t.Errorf("isJSON(%q) = %v, ожидалось %v", tt.input, got, tt.want)
 			}
 		})
 	}
 }
 
+func TestIsJSON_Limit(t *testing.T) {
+	obj := `{"key":"` + strings.Repeat("x", 100) + `"}`
+	if !isJSON(obj, len(obj)) {
+		t.Error("строка длиной в предел должна проверяться")
+	}
+	if isJSON(obj, len(obj)-1) {
+		t.Error("строка длиннее предела не должна считаться JSON")
+	}
+	if isJSON(`{}`, 0) || isJSON(`{}`, NoJSONSniffing) {
+		t.Error("при limit <= 0 распознавание должно быть отключено")
+	}
+}
+
+func TestFormatAnyValue_SniffLimit(t *testing.T) {
+	const obj = `{"a":1}`
+	tests := []struct {
+		limit   int
+		wantRaw bool
+	}{
+		{0, true},
+		{NoJSONSniffing, false},
+		{3, false},
+		{len(obj), true},
+	}
+	for _, tt := range tests {
+		h := &ColorHandler{JSONSniffLimit: tt.limit}
+		_, isRaw := formatAnyValue(obj, h.jsonSniffLimit(), nil).(json.RawMessage)
+		if isRaw != tt.wantRaw {
+			t.Errorf("JSONSniffLimit=%d: json.RawMessage=%v, ожидалось %v", tt.limit, isRaw, tt.wantRaw)
+		}
+	}
+}
+
 // ──────────────────────────────────────────────────────────
 // Группа-атрибут (вложенная группа в записи)
 // ──────────────────────────────────────────────────────────
@@ -522,3 +705,22 @@ func TestSlogLogger_WithAttrsIntegration(t *testing.T) {
 		t.Errorf("интеграция With: нет атрибута env=staging: %s", out)
 	}
 }
+
+// ──────────────────────────────────────────────────────────
+// appendDuration
+// ──────────────────────────────────────────────────────────
+
+func TestAppendDuration(t *testing.T) {
+	durations := []time.Duration{
+		0, 1, 999, time.Microsecond, 1500 * time.Nanosecond, time.Millisecond,
+		1200 * time.Microsecond, time.Second, 90 * time.Second, -5 * time.Minute,
+		26*time.Hour + 3*time.Minute + 4*time.Second + 5*time.Millisecond,
+		time.Duration(1<<63 - 1), time.Duration(-1 << 63),
+	}
+
+	for _, d := range durations {
+		if got, want := string(appendDuration(nil, d)), d.String(); got != want {
+			t.Errorf("appendDuration(%d) = %q, ожидалось %q", int64(d), got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+)
+
+// Target - handler с собственным минимальным уровнем для MultiHandler
+type Target struct {
+	Handler slog.Handler
+	Level   slog.Leveler // nil - уровень не ограничивается
+}
+
+// MultiHandler рассылает каждую запись нескольким handler'ам, например
+// цветной вывод в терминал и JSON в файл:
+//
+//	log := slog.New(logger.NewMultiHandler(
+//		logger.Target{Handler: logger.NewColorHandler(os.Stdout)},
+//		logger.Target{Handler: slog.NewJSONHandler(file, nil), Level: slog.LevelInfo},
+//	))
+type MultiHandler struct {
+	targets []Target
+}
+
+// NewMultiHandler создает MultiHandler для переданных handler'ов
+func NewMultiHandler(targets ...Target) *MultiHandler {
+	return &MultiHandler{targets: slices.Clone(targets)}
+}
+
+// enabled проверяет уровень target'а и сам handler
+func (t Target) enabled(ctx context.Context, level slog.Level) bool {
+	if t.Level != nil && level < t.Level.Level() {
+		return false
+	}
+	return t.Handler.Enabled(ctx, level)
+}
+
+// Enabled возвращает true, если запись нужна хотя бы одному handler'у
+func (m *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, t := range m.targets {
+		if t.enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle передает копию записи каждому handler'у, которому она нужна.
+// Ошибки всех handler'ов объединяются через errors.Join.
+func (m *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, t := range m.targets {
+		if !t.enabled(ctx, r.Level) {
+			continue
+		}
+		// Handler'ы могут менять запись (AddAttrs), поэтому каждому своя копия
+		if err := t.Handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs реализует slog.HandlerWithAttrs для всех handler'ов
+func (m *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return m.derive(func(h slog.Handler) slog.Handler {
+		return h.WithAttrs(slices.Clone(attrs))
+	})
+}
+
+// WithGroup реализует slog.HandlerWithGroup для всех handler'ов
+func (m *MultiHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return m
+	}
+	return m.derive(func(h slog.Handler) slog.Handler {
+		return h.WithGroup(name)
+	})
+}
+
+// derive создает MultiHandler с преобразованными handler'ами и теми же уровнями
+func (m *MultiHandler) derive(fn func(slog.Handler) slog.Handler) *MultiHandler {
+	targets := make([]Target, len(m.targets))
+	for i, t := range m.targets {
+		targets[i] = Target{Handler: fn(t.Handler), Level: t.Level}
+	}
+	return &MultiHandler{targets: targets}
+}
+
+// Flush вызывает Flush у handler'ов, которые его поддерживают
+func (m *MultiHandler) Flush() error {
+	var errs []error
+	for _, t := range m.targets {
+		if f, ok := t.Handler.(interface{ Flush() error }); ok {
+			errs = append(errs, f.Flush())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close вызывает Close у handler'ов, которые его поддерживают
+func (m *MultiHandler) Close() error {
+	var errs []error
+	for _, t := range m.targets {
+		if c, ok := t.Handler.(interface{ Close() error }); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// ──────────────────────────────────────────────────────────
+// Хелперы
+// ──────────────────────────────────────────────────────────
+
+// mutatingHandler дописывает атрибут в полученную запись и запоминает ее
+type mutatingHandler struct {
+	records []slog.Record
+	err     error
+}
+
+func (h *mutatingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *mutatingHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *mutatingHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *mutatingHandler) Handle(_ context.Context, r slog.Record) error {
+	r.AddAttrs(slog.String("mutated", "yes"))
+	h.records = append(h.records, r)
+	return h.err
+}
+
+// ──────────────────────────────────────────────────────────
+// MultiHandler
+// ──────────────────────────────────────────────────────────
+
+func TestMultiHandler_ColorAndJSON(t *testing.T) {
+	var colorOut, jsonOut bytes.Buffer
+	log := slog.New(NewMultiHandler(
+		Target{Handler: NewColorHandler(&colorOut)},
+		Target{Handler: slog.NewJSONHandler(&jsonOut, nil)},
+	))
+
+	log.With("service", "api").WithGroup("http").Info("request", "status", 200)
+
+	if out := colorOut.String(); !strings.Contains(out, "INF http.request service=api status=200") {
+		t.Errorf("цветной вывод: %q", out)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(jsonOut.Bytes(), &got); err != nil {
+		t.Fatalf("JSON-вывод не разобран: %v (%q)", err, jsonOut.String())
+	}
+	if got["service"] != "api" {
+		t.Errorf("JSON: нет атрибута service из With: %v", got)
+	}
+	if grp, _ := got["http"].(map[string]any); grp["status"] != float64(200) {
+		t.Errorf("JSON: нет атрибута http.status: %v", got)
+	}
+}
+
+func TestMultiHandler_PerTargetLevel(t *testing.T) {
+	var all, errsOnly bytes.Buffer
+	m := NewMultiHandler(
+		Target{Handler: NewColorHandler(&all)},
+		Target{Handler: NewColorHandler(&errsOnly), Level: slog.LevelError},
+	)
+	log := slog.New(m)
+
+	log.Info("info line")
+	log.Error("error line")
+
+	if strings.Count(all.String(), "\n") != 2 {
+		t.Errorf("первый handler должен получить обе записи: %q", all.String())
+	}
+	if out := errsOnly.String(); strings.Contains(out, "info line") || !strings.Contains(out, "error line") {
+		t.Errorf("второй handler должен получить только ошибку: %q", out)
+	}
+}
+
+func TestMultiHandler_Enabled(t *testing.T) {
+	m := NewMultiHandler(
+		Target{Handler: NewColorHandler(&bytes.Buffer{}), Level: slog.LevelWarn},
+		Target{Handler: slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelError})},
+	)
+	ctx := context.Background()
+
+	if m.Enabled(ctx, slog.LevelInfo) {
+		t.Error("Enabled(Info) = true, ни один handler не принимает Info")
+	}
+	if !m.Enabled(ctx, slog.LevelWarn) {
+		t.Error("Enabled(Warn) = false, первый handler принимает Warn")
+	}
+}
+
+func TestMultiHandler_ClonesRecord(t *testing.T) {
+	first, second := &mutatingHandler{}, &mutatingHandler{}
+	m := NewMultiHandler(Target{Handler: first}, Target{Handler: second})
+
+	r := newTestRecord(slog.LevelInfo, "shared")
+	r.AddAttrs(slog.Int("a", 1), slog.Int("b", 2), slog.Int("c", 3), slog.Int("d", 4), slog.Int("e", 5), slog.Int("f", 6))
+	_ = m.Handle(context.Background(), r)
+
+	if r.NumAttrs() != 6 {
+		t.Errorf("исходная запись изменена: %d атрибутов", r.NumAttrs())
+	}
+	for i, h := range []*mutatingHandler{first, second} {
+		if n := h.records[0].NumAttrs(); n != 7 {
+			t.Errorf("handler %d получил %d атрибутов, ожидалось 7", i, n)
+		}
+	}
+}
+
+func TestMultiHandler_JoinsErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	m := NewMultiHandler(
+		Target{Handler: &mutatingHandler{err: errA}},
+		Target{Handler: &mutatingHandler{err: errB}},
+	)
+
+	err := m.Handle(context.Background(), newTestRecord(slog.LevelInfo, "msg"))
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Handle() = %v, ожидались обе ошибки", err)
+	}
+}
+
+func TestMultiHandler_Close(t *testing.T) {
+	w1, w2 := &lifecycleWriter{}, &lifecycleWriter{}
+	m := NewMultiHandler(
+		Target{Handler: NewColorHandler(w1)},
+		Target{Handler: NewColorHandler(w2)},
+	)
+
+	if err := m.Flush(); err != nil {
+		t.Fatalf("Flush вернул ошибку: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+	if w1.closes != 1 || w2.closes != 1 {
+		t.Errorf("Close должен дойти до всех writer'ов: %d, %d", w1.closes, w2.closes)
+	}
+	if m.Enabled(context.Background(), slog.LevelError) {
+		t.Error("после Close все handler'ы выключены, Enabled должен вернуть false")
+	}
+}
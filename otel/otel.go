@@ -0,0 +1,37 @@
+// Package otel добавляет к записям ColorHandler идентификаторы трейса
+// и спана OpenTelemetry. Вынесен из основного пакета, чтобы тот не зависел
+// от OpenTelemetry:
+//
+//	h := logger.NewColorHandler(os.Stdout)
+//	h.ContextExtractors = append(h.ContextExtractors, otel.Extractor)
+package otel
+
+import (
+	"context"
+	"log/slog"
+
+	logger "github.com/golub15/slog_color"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Ключи атрибутов с идентификаторами OpenTelemetry
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+var _ logger.ContextExtractor = Extractor
+
+// Extractor возвращает trace_id и span_id, если контекст содержит валидный
+// span context OpenTelemetry. Значения - logger.ShortID: в цветном формате
+// они сокращены и приглушены, в JSON и logfmt выводятся полностью.
+func Extractor(ctx context.Context) []slog.Attr {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []slog.Attr{
+		slog.Any(TraceIDKey, logger.ShortID(sc.TraceID().String())),
+		slog.Any(SpanIDKey, logger.ShortID(sc.SpanID().String())),
+	}
+}
@@ -0,0 +1,73 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	logger "github.com/golub15/slog_color"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanContext возвращает контекст с локально созданным span context,
+// без SDK и коллектора
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestExtractor(t *testing.T) {
+	got := Extractor(spanContext(t))
+	want := []slog.Attr{
+		slog.Any(TraceIDKey, logger.ShortID("4bf92f3577b34da6a3ce929d0e0e4736")),
+		slog.Any(SpanIDKey, logger.ShortID("00f067aa0ba902b7")),
+	}
+	if len(got) != len(want) || !got[0].Equal(want[0]) || !got[1].Equal(want[1]) {
+		t.Errorf("Extractor = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestExtractor_NoSpan(t *testing.T) {
+	// Невалидный span context (нулевые идентификаторы) не выводится
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.SpanContext{})
+	if got := Extractor(ctx); got != nil {
+		t.Errorf("без span context идентификаторы не добавляются: %v", got)
+	}
+	if got := Extractor(context.Background()); got != nil {
+		t.Errorf("без span context идентификаторы не добавляются: %v", got)
+	}
+}
+
+func TestExtractor_OutsideGroups(t *testing.T) {
+	tests := map[logger.Format]string{
+		logger.FormatJSON:   `"msg":"m","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","http":{"status":200}}`,
+		logger.FormatLogfmt: ` msg=m http.status=200 trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7`,
+	}
+	for format, want := range tests {
+		var buf bytes.Buffer
+		h := logger.NewColorHandler(&buf)
+		h.Format = format
+		h.ContextExtractors = []logger.ContextExtractor{Extractor}
+
+		// Идентификаторы относятся ко всей записи и не попадают в группу
+		slog.New(h).WithGroup("http").InfoContext(spanContext(t), "m", "status", 200)
+		if !strings.HasSuffix(buf.String(), want+"\n") {
+			t.Errorf("формат %d: %q, ожидалось окончание %q", format, buf.String(), want)
+		}
+	}
+}
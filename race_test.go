@@ -0,0 +1,7 @@
+//go:build race
+
+package logger
+
+// raceEnabled: под race-детектором sync.Pool случайно отбрасывает объекты,
+// поэтому замеры аллокаций не имеют смысла
+const raceEnabled = true
@@ -0,0 +1,374 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat - метка времени в имени ротированного файла
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions настраивает RotatingFile
+type RotateOptions struct {
+	// MaxSize - максимальный размер файла в байтах (0 - без ограничения)
+	MaxSize int64
+	// MaxAge - ротация, когда файл старше MaxAge (0 - без ограничения).
+	// Возраст уже существующего файла отсчитывается от последней ротации
+	// (или от его изменения), а не от перезапуска.
+	MaxAge time.Duration
+	// MaxBackups - сколько ротированных файлов хранить (0 - все)
+	MaxBackups int
+	// Compress - сжимать ротированные файлы gzip
+	Compress bool
+}
+
+// RotatingFile - файл лога с ротацией по размеру и возрасту.
+// Ротированные файлы называются app-2006-01-02T15-04-05.000.log
+// (и .log.gz при Compress).
+//
+// У RotatingFile своя блокировка, независимая от мьютекса handler'а:
+// ротация по сигналу не ждет handler, а сжатие и удаление старых файлов
+// выполняются в фоне без блокировок записи.
+//
+//	f, err := logger.OpenRotatingFile("/var/log/app.log", logger.RotateOptions{
+//		MaxSize:    100 << 20,
+//		MaxBackups: 7,
+//		Compress:   true,
+//	})
+//	defer f.Close()
+//	stop := f.ReopenOnSignal()
+//	defer stop()
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+	now  func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+	closed bool
+
+	postMu sync.Mutex     // сжатие и очистка выполняются по очереди
+	wg     sync.WaitGroup // фоновые сжатие и очистка
+}
+
+// OpenRotatingFile открывает (или создает) файл path для дозаписи
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, opts: opts, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if f.size > 0 {
+		// Иначе сервис, перезапускающийся чаще MaxAge, не ротировал бы лог
+		f.opened = f.startedAt()
+	}
+	return f, nil
+}
+
+// startedAt оценивает, когда начат существующий файл: текущий файл
+// появляется при ротации, поэтому берется метка самой новой копии,
+// если она раньше времени изменения файла, иначе время изменения
+func (f *RotatingFile) startedAt() time.Time {
+	start := f.opened
+	if info, err := f.file.Stat(); err == nil {
+		start = info.ModTime()
+	}
+	names, err := f.backups()
+	if err != nil || len(names) == 0 {
+		return start
+	}
+	_, prefix, ext := f.nameParts()
+	stamp := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(names[0]), ".gz"), ext)
+	t, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(stamp, prefix), time.Local)
+	if err == nil && t.Before(start) {
+		start = t
+	}
+	return start
+}
+
+// open открывает f.path; вызывается под f.mu
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.now()
+	return nil
+}
+
+// Write дописывает p в файл, предварительно выполняя ротацию, если
+// запись превысит MaxSize или файл старше MaxAge
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, ErrClosed
+	}
+
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// shouldRotate сообщает, нужна ли ротация перед записью n байт
+func (f *RotatingFile) shouldRotate(n int) bool {
+	if f.size == 0 {
+		// Пустой файл не ротируем, даже если одна строка больше MaxSize
+		return false
+	}
+	if f.opts.MaxSize > 0 && f.size+int64(n) > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.MaxAge > 0 && f.now().Sub(f.opened) >= f.opts.MaxAge
+}
+
+// Rotate принудительно переименовывает текущий файл и открывает новый
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+	return f.rotate()
+}
+
+// rotate выполняет ротацию; вызывается под f.mu
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	backup := f.backupName(f.now())
+	if err := os.Rename(f.path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		// Не удалось переименовать: продолжаем писать в прежний файл
+		if openErr := f.open(); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.postRotate(backup)
+	}()
+	return nil
+}
+
+// Reopen закрывает и заново открывает файл по тому же пути.
+// Нужен, когда файл переименовал внешний logrotate.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// reopenOnSignals вызывает Reopen при получении сигналов sigs (не пустых:
+// signal.Notify без сигналов подписывается на все).
+// Возвращает функцию, прекращающую обработку сигналов.
+func (f *RotatingFile) reopenOnSignals(sigs []os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = f.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Sync сбрасывает содержимое файла на диск
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Close закрывает файл и ждет завершения фонового сжатия и очистки.
+// Повторный вызов ничего не делает.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	err := f.file.Close()
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
+
+// backupName возвращает имя ротированного файла для момента t.
+// Если такое имя уже занято, метка сдвигается на миллисекунду.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	for {
+		name := filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// nameParts разбивает путь /dir/app.log на "/dir", "app-" и ".log"
+func (f *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir, name := filepath.Split(f.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// postRotate сжимает ротированный файл и удаляет лишние копии
+func (f *RotatingFile) postRotate(backup string) {
+	f.postMu.Lock()
+	defer f.postMu.Unlock()
+
+	if f.opts.Compress {
+		_ = compressFile(backup)
+	}
+	if f.opts.MaxBackups > 0 {
+		_ = f.pruneBackups()
+	}
+}
+
+// backups возвращает ротированные файлы, от новых к старым
+func (f *RotatingFile) backups() ([]string, error) {
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp, ok := strings.CutSuffix(strings.TrimSuffix(name, ".gz"), ext)
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(stamp, prefix)); err != nil {
+			continue
+		}
+		names = append(names, filepath.Join(dir, name))
+	}
+
+	// Метка времени сортируется лексикографически
+	slices.Sort(names)
+	slices.Reverse(names)
+	return names, nil
+}
+
+// pruneBackups оставляет только MaxBackups самых новых файлов
+func (f *RotatingFile) pruneBackups() error {
+	names, err := f.backups()
+	if err != nil {
+		return err
+	}
+	if len(names) <= f.opts.MaxBackups {
+		return nil
+	}
+
+	var errs []error
+	for _, name := range names[f.opts.MaxBackups:] {
+		errs = append(errs, os.Remove(name))
+	}
+	return errors.Join(errs...)
+}
+
+// compressFile сжимает path в path.gz и удаляет исходный файл
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(path + ".gz")
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
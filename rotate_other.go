@@ -0,0 +1,15 @@
+//go:build !unix
+
+package logger
+
+import "os"
+
+// ReopenOnSignal вызывает Reopen при получении сигналов sigs.
+// Сигнала по умолчанию на этой платформе нет: без аргументов
+// ничего не делает. Возвращает функцию, прекращающую обработку сигналов.
+func (f *RotatingFile) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		return func() {}
+	}
+	return f.reopenOnSignals(sigs)
+}
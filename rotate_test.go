@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ──────────────────────────────────────────────────────────
+// Хелперы
+// ──────────────────────────────────────────────────────────
+
+// fakeClock - управляемые часы для RotatingFile
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// openTestRotatingFile открывает RotatingFile во временном каталоге с фиктивными часами
+func openTestRotatingFile(t *testing.T, opts RotateOptions) (*RotatingFile, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Date(2026, 2, 8, 12, 30, 45, 0, time.UTC)}
+
+	f, err := OpenRotatingFile(filepath.Join(t.TempDir(), "app.log"), opts)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile вернул ошибку: %v", err)
+	}
+	f.now = clock.now
+	f.opened = clock.now()
+	t.Cleanup(func() { f.Close() })
+	return f, clock
+}
+
+// listBackups возвращает имена ротированных файлов (без каталога)
+func listBackups(t *testing.T, f *RotatingFile) []string {
+	t.Helper()
+	names, err := f.backups()
+	if err != nil {
+		t.Fatalf("backups вернул ошибку: %v", err)
+	}
+	for i, name := range names {
+		names[i] = filepath.Base(name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("не удалось прочитать %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// ──────────────────────────────────────────────────────────
+// RotatingFile
+// ──────────────────────────────────────────────────────────
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	f, clock := openTestRotatingFile(t, RotateOptions{MaxSize: 10})
+
+	_, _ = f.Write([]byte("12345678\n"))
+	clock.advance(time.Second)
+	_, _ = f.Write([]byte("abcdefgh\n")) // 18 > 10: ротация перед записью
+
+	want := []string{"app-2026-02-08T12-30-46.000.log"}
+	if got := listBackups(t, f); !slices.Equal(got, want) {
+		t.Fatalf("ротированные файлы = %v, ожидалось %v", got, want)
+	}
+
+	dir := filepath.Dir(f.path)
+	if got := readFile(t, filepath.Join(dir, want[0])); got != "12345678\n" {
+		t.Errorf("старый файл = %q", got)
+	}
+	if got := readFile(t, f.path); got != "abcdefgh\n" {
+		t.Errorf("новый файл = %q", got)
+	}
+}
+
+func TestRotatingFile_RotatesByAge(t *testing.T) {
+	f, clock := openTestRotatingFile(t, RotateOptions{MaxAge: time.Hour})
+
+	_, _ = f.Write([]byte("first\n"))
+	clock.advance(30 * time.Minute)
+	_, _ = f.Write([]byte("second\n"))
+	if got := listBackups(t, f); len(got) != 0 {
+		t.Fatalf("ротация раньше MaxAge: %v", got)
+	}
+
+	clock.advance(30 * time.Minute)
+	_, _ = f.Write([]byte("third\n"))
+	if got := listBackups(t, f); len(got) != 1 {
+		t.Fatalf("ожидалась одна ротация по возрасту, получено %v", got)
+	}
+	if got := readFile(t, f.path); got != "third\n" {
+		t.Errorf("новый файл = %q", got)
+	}
+}
+
+func TestRotatingFile_AgeOfExistingFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		modAgo time.Duration // сколько назад изменялся файл
+		backup time.Duration // сколько назад была последняя ротация (0 - не было)
+	}{
+		{"ModTime", 2 * time.Hour, 0},
+		{"LastRotation", time.Minute, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			now := time.Now()
+			if err := os.Chtimes(path, now, now.Add(-tt.modAgo)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.backup > 0 {
+				name := "app-" + now.Add(-tt.backup).Format(backupTimeFormat) + ".log"
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Перезапуск: файл открывается заново, но его возраст сохраняется
+			f, err := OpenRotatingFile(path, RotateOptions{MaxAge: time.Hour})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			_, _ = f.Write([]byte("new\n"))
+
+			if got := readFile(t, path); got != "new\n" {
+				t.Errorf("файл старше MaxAge не ротирован: %q", got)
+			}
+		})
+	}
+}
+
+func TestRotatingFile_MaxBackupsAndCompress(t *testing.T) {
+	f, clock := openTestRotatingFile(t, RotateOptions{MaxBackups: 2, Compress: true})
+
+	for i := range 4 {
+		_, _ = f.Write([]byte(strings.Repeat("x", i+1) + "\n"))
+		clock.advance(time.Second)
+		if err := f.Rotate(); err != nil {
+			t.Fatalf("Rotate вернул ошибку: %v", err)
+		}
+	}
+	_ = f.Close() // дожидаемся фонового сжатия и очистки
+
+	want := []string{
+		"app-2026-02-08T12-30-48.000.log.gz",
+		"app-2026-02-08T12-30-49.000.log.gz",
+	}
+	if got := listBackups(t, f); !slices.Equal(got, want) {
+		t.Fatalf("ротированные файлы = %v, ожидалось %v", got, want)
+	}
+
+	gzFile, err := os.Open(filepath.Join(filepath.Dir(f.path), want[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gzFile.Close()
+	zr, err := gzip.NewReader(gzFile)
+	if err != nil {
+		t.Fatalf("файл не является gzip: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	if string(data) != "xxxx\n" {
+		t.Errorf("содержимое сжатого файла = %q", data)
+	}
+}
+
+func TestRotatingFile_WithColorHandler(t *testing.T) {
+	f, _ := openTestRotatingFile(t, RotateOptions{MaxSize: 64})
+	h := NewColorHandler(f)
+
+	// Строка занимает 32 байта: по две в файле. Часы стоят, но имена
+	// ротированных файлов не должны совпасть
+	for range 10 {
+		_ = h.Handle(t.Context(), newTestRecord(slog.LevelInfo, "rotating message"))
+	}
+	if got := listBackups(t, f); len(got) != 4 {
+		t.Errorf("ожидалось 4 ротированных файлов, получено %d: %v", len(got), got)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+
+	if _, err := f.Write([]byte("late\n")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write после Close handler'а = %v, ожидалось ErrClosed", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// ReopenOnSignal вызывает Reopen при получении сигналов (по умолчанию SIGHUP).
+// Возвращает функцию, прекращающую обработку сигналов.
+func (f *RotatingFile) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	return f.reopenOnSignals(sigs)
+}
@@ -0,0 +1,46 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_ReopenOnSignal(t *testing.T) {
+	f, _ := openTestRotatingFile(t, RotateOptions{})
+	stop := f.ReopenOnSignal(syscall.SIGUSR1)
+	defer stop()
+
+	_, _ = f.Write([]byte("before\n"))
+
+	// Внешний logrotate переименовал файл и прислал сигнал
+	moved := f.path + ".1"
+	if err := os.Rename(f.path, moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(f.path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("файл не переоткрыт после сигнала")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	_, _ = f.Write([]byte("after\n"))
+	if got := readFile(t, moved); got != "before\n" {
+		t.Errorf("переименованный файл = %q", got)
+	}
+	if got := readFile(t, f.path); got != "after\n" {
+		t.Errorf("новый файл = %q", got)
+	}
+}
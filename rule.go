@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+)
+
+// Rule - правило выделения атрибута в цветном выводе.
+//
+// Key сравнивается с ключом атрибута (без префикса групп): точно или,
+// если содержит *, ? или [, как шаблон path.Match ("*_id", "err*").
+// Пустой Key подходит к любому ключу. Если задан Match, правило
+// применяется только к значениям, для которых он вернул true.
+//
+// KeyStyle и ValueStyle заменяют оформление ключа и значения;
+// пустой стиль оставляет оформление по умолчанию.
+type Rule struct {
+	Key        string
+	Match      func(v slog.Value) bool
+	KeyStyle   Style
+	ValueStyle Style
+}
+
+// matches проверяет, подходит ли правило к атрибуту
+func (r *Rule) matches(a slog.Attr) bool {
+	if r.Key != "" && r.Key != a.Key {
+		if !strings.ContainsAny(r.Key, "*?[") {
+			return false
+		}
+		if ok, _ := path.Match(r.Key, a.Key); !ok {
+			return false
+		}
+	}
+	return r.Match == nil || r.Match(a.Value)
+}
+
+// ruleFor возвращает первое подходящее правило или nil
+func (h *ColorHandler) ruleFor(a slog.Attr) *Rule {
+	for i := range h.Rules {
+		if h.Rules[i].matches(a) {
+			return &h.Rules[i]
+		}
+	}
+	return nil
+}
+
+// ──────────────────────────────────────────────────────────
+// Предикаты для Rule.Match
+// ──────────────────────────────────────────────────────────
+
+// AtLeast подходит к числам >= n
+func AtLeast(n float64) func(slog.Value) bool {
+	return func(v slog.Value) bool {
+		f, ok := numberOf(v)
+		return ok && f >= n
+	}
+}
+
+// Below подходит к числам < n
+func Below(n float64) func(slog.Value) bool {
+	return func(v slog.Value) bool {
+		f, ok := numberOf(v)
+		return ok && f < n
+	}
+}
+
+// Between подходит к числам из диапазона [lo, hi]
+func Between(lo, hi float64) func(slog.Value) bool {
+	return func(v slog.Value) bool {
+		f, ok := numberOf(v)
+		return ok && f >= lo && f <= hi
+	}
+}
+
+// LongerThan подходит к длительностям больше d
+func LongerThan(d time.Duration) func(slog.Value) bool {
+	return func(v slog.Value) bool {
+		return v.Kind() == slog.KindDuration && v.Duration() > d
+	}
+}
+
+// numberOf возвращает числовое значение целых и вещественных атрибутов
+func numberOf(v slog.Value) (float64, bool) {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64()), true
+	case slog.KindUint64:
+		return float64(v.Uint64()), true
+	case slog.KindFloat64:
+		return v.Float64(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+var (
+	redStyle   = NewStyle(color.FgRed)
+	greenStyle = NewStyle(color.FgGreen)
+	boldStyle  = NewStyle(color.FgMagenta, color.Bold, color.Underline)
+)
+
+// testRules - правила из описания задачи
+var testRules = []Rule{
+	{Key: "err*", KeyStyle: redStyle, ValueStyle: redStyle},
+	{Key: "status", Match: AtLeast(500), ValueStyle: redStyle},
+	{Key: "status", Match: Between(200, 299), ValueStyle: greenStyle},
+	{Key: "duration", Match: LongerThan(time.Second), ValueStyle: NewStyle(color.FgYellow)},
+	{Key: "user_id", ValueStyle: boldStyle},
+}
+
+// renderAttr возвращает цветной вывод одного атрибута
+func renderAttr(t *testing.T, rules []Rule, a slog.Attr) string {
+	t.Helper()
+	h, buf := newTestHandler()
+	h.Rules = rules
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(a)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	_, attr, _ := strings.Cut(strings.TrimSuffix(buf.String(), "\n"), "m"+ansiReset)
+	return attr
+}
+
+func TestRules_Styles(t *testing.T) {
+	withColors(t)
+
+	def := func(key, value string) string {
+		return string(keyStyle) + " " + key + "=" + ansiReset + string(valueStyle) + value + ansiReset
+	}
+	styled := func(ks, vs Style, key, value string) string {
+		return string(ks) + " " + key + "=" + ansiReset + string(vs) + value + ansiReset
+	}
+
+	tests := []struct {
+		attr slog.Attr
+		want string
+	}{
+		{slog.String("err", "boom"), styled(redStyle, redStyle, "err", "boom")},
+		{slog.String("error", "boom"), styled(redStyle, redStyle, "error", "boom")},
+		{slog.Int("status", 503), styled(keyStyle, redStyle, "status", "503")},
+		{slog.Int("status", 204), styled(keyStyle, greenStyle, "status", "204")},
+		{slog.Int("status", 404), def("status", "404")},
+		{slog.String("status", "500"), def("status", "500")},
+		{slog.Duration("duration", 2*time.Second), styled(keyStyle, NewStyle(color.FgYellow), "duration", "2s")},
+		{slog.Duration("duration", time.Millisecond), def("duration", "1ms")},
+		{slog.Int("user_id", 7), styled(keyStyle, boldStyle, "user_id", "7")},
+		{slog.Int("user", 7), def("user", "7")},
+	}
+	for _, tt := range tests {
+		if got := renderAttr(t, testRules, tt.attr); got != tt.want {
+			t.Errorf("%v:\n%q\nожидалось\n%q", tt.attr, got, tt.want)
+		}
+	}
+}
+
+func TestRules_FirstMatchWins(t *testing.T) {
+	withColors(t)
+
+	rules := []Rule{
+		{Key: "*", Match: AtLeast(10), ValueStyle: redStyle},
+		{Key: "n", ValueStyle: greenStyle},
+	}
+	if got := renderAttr(t, rules, slog.Int("n", 42)); !strings.Contains(got, string(redStyle)+"42") {
+		t.Errorf("должно примениться первое правило: %q", got)
+	}
+	if got := renderAttr(t, rules, slog.Int("n", 1)); !strings.Contains(got, string(greenStyle)+"1") {
+		t.Errorf("должно примениться второе правило: %q", got)
+	}
+}
+
+func TestRules_NoColors(t *testing.T) {
+	h, buf := newTestHandler()
+	h.Rules = testRules
+	r := newTestRecord(slog.LevelError, "failed")
+	r.AddAttrs(slog.Int("status", 503), slog.String("err", "boom"))
+	_ = h.Handle(context.Background(), r)
+
+	if want := "[12:30:45] ERR failed status=503 err=boom\n"; buf.String() != want {
+		t.Errorf("без цветов правила не должны влиять на вывод: %q", buf.String())
+	}
+}
+
+func TestRules_AppliedToPreformatted(t *testing.T) {
+	withColors(t)
+
+	h, buf := newTestHandler()
+	h.Rules = testRules
+	_ = h.WithAttrs([]slog.Attr{slog.Int("user_id", 7)}).Handle(context.Background(), newTestRecord(slog.LevelInfo, "m"))
+	if !strings.Contains(buf.String(), string(boldStyle)+"7") {
+		t.Errorf("правила должны применяться к атрибутам из WithAttrs: %q", buf.String())
+	}
+}
+
+func TestRulePredicates(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(slog.Value) bool
+		v    slog.Value
+		want bool
+	}{
+		{"AtLeast int", AtLeast(500), slog.IntValue(500), true},
+		{"AtLeast uint", AtLeast(500), slog.Uint64Value(499), false},
+		{"AtLeast float", AtLeast(0.5), slog.Float64Value(0.75), true},
+		{"AtLeast string", AtLeast(1), slog.StringValue("7"), false},
+		{"Below", Below(300), slog.IntValue(299), true},
+		{"Between", Between(200, 299), slog.IntValue(299), true},
+		{"Between outside", Between(200, 299), slog.IntValue(300), false},
+		{"LongerThan", LongerThan(time.Second), slog.DurationValue(time.Second), false},
+		{"LongerThan int", LongerThan(time.Second), slog.Int64Value(int64(2 * time.Second)), false},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.v); got != tt.want {
+			t.Errorf("%s(%v) = %v, ожидалось %v", tt.name, tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"log/slog"
+
+	"github.com/fatih/color"
+)
+
+// ShortIDLen - сколько символов ShortID выводится в цветном формате:
+// этого хватает, чтобы найти запись по идентификатору, и строка не раздувается
+const ShortIDLen = 8
+
+// idStyle - приглушенный цвет ShortID
+var idStyle = NewStyle(color.FgHiBlack)
+
+// ShortID - идентификатор (трейса, спана, запроса), который цветной формат
+// выводит сокращенным до ShortIDLen символов и приглушенным. В JSON
+// и logfmt, как и в других handler'ах, он выводится полностью.
+type ShortID string
+
+// shortIDOf возвращает сокращенный идентификатор, если значение - ShortID
+func shortIDOf(v slog.Value) (string, bool) {
+	if v.Kind() != slog.KindAny {
+		return "", false
+	}
+	id, ok := v.Any().(ShortID)
+	if !ok {
+		return "", false
+	}
+	if len(id) > ShortIDLen {
+		id = id[:ShortIDLen]
+	}
+	return string(id), true
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const testTraceID = ShortID("4bf92f3577b34da6a3ce929d0e0e4736")
+
+func TestShortID_ColorShortened(t *testing.T) {
+	h, buf := newTestHandler()
+	slog.New(h).Info("m", "k", 1, "trace_id", testTraceID, "req", ShortID("r-1"))
+
+	if want := " INF m k=1 trace_id=4bf92f35 req=r-1\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("идентификаторы:\n%q\nожидалось окончание\n%q", buf.String(), want)
+	}
+}
+
+func TestShortID_Dimmed(t *testing.T) {
+	withColors(t)
+	h, buf := newTestHandler()
+	h.Theme = KindTheme()
+	slog.New(h).Info("m", "trace_id", testTraceID)
+
+	want := string(idStyle) + " trace_id=" + ansiReset + string(idStyle) + "4bf92f35" + ansiReset
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("идентификатор должен быть приглушен:\n%q\nожидалось\n%q", buf.String(), want)
+	}
+}
+
+func TestShortID_FullInJSONAndLogfmt(t *testing.T) {
+	tests := map[Format]string{
+		FormatJSON:   `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"}`,
+		FormatLogfmt: ` trace_id=4bf92f3577b34da6a3ce929d0e0e4736`,
+	}
+	for format, want := range tests {
+		h, buf := newTestHandler()
+		h.Format = format
+		r := newTestRecord(slog.LevelInfo, "m")
+		r.AddAttrs(slog.Any("trace_id", testTraceID))
+		_ = h.Handle(context.Background(), r)
+
+		if !strings.HasSuffix(buf.String(), want+"\n") {
+			t.Errorf("формат %d: %q, ожидалось окончание %q", format, buf.String(), want)
+		}
+	}
+}
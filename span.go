@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Ключи атрибутов, которые добавляет Span.End
+const (
+	ElapsedKey = "elapsed"
+	StatusKey  = "status"
+)
+
+// Пороги и цвета длительности спана: быстрые операции зеленые,
+// заметные - желтые, медленные - красные
+const (
+	elapsedSlow    = time.Second
+	elapsedNoticed = 100 * time.Millisecond
+)
+
+var (
+	elapsedFastStyle    = NewStyle(color.FgHiGreen)
+	elapsedNoticedStyle = NewStyle(color.FgHiYellow)
+	elapsedSlowStyle    = NewStyle(color.FgHiRed)
+)
+
+// Span замеряет длительность операции: Start выводит строку начала,
+// End - строку завершения с длительностью. Записи с контекстом спана
+// (Context) и вложенные спаны выводятся ColorHandler'ом на уровень
+// глубже, строка завершения закрывает ветку дерева.
+type Span struct {
+	log   *slog.Logger
+	ctx   context.Context // контекст, в котором начат спан
+	inner context.Context // контекст вложенных записей: на уровень глубже
+	name  string
+	start time.Time
+	ended atomic.Bool
+}
+
+// Start выводит через log строку начала операции name с атрибутами args
+// и возвращает Span. Вложенный спан начинается с контекстом родителя:
+//
+//	span := logger.Start(ctx, log, "import users")
+//	defer span.End()
+//	page := logger.Start(span.Context(), log, "fetch page")
+//
+// Если log равен nil, используется slog.Default().
+func Start(ctx context.Context, log *slog.Logger, name string, args ...any) *Span {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+	s := &Span{
+		log:   log,
+		ctx:   ctx,
+		inner: Nest(ctx),
+		name:  name,
+		start: time.Now(),
+	}
+	s.emit(s.ctx, slog.LevelInfo, args)
+	return s
+}
+
+// Context возвращает контекст для записей и спанов внутри операции
+func (s *Span) Context() context.Context {
+	return s.inner
+}
+
+// End выводит строку завершения с длительностью и атрибутами args
+// и возвращает длительность. Если среди args есть ошибка (значение error
+// или slog.Attr с ним), строка выводится уровнем ERROR со status=error.
+// Повторные вызовы ничего не выводят.
+func (s *Span) End(args ...any) time.Duration {
+	elapsed := time.Since(s.start)
+	if s.ended.Swap(true) {
+		return elapsed
+	}
+
+	level := slog.LevelInfo
+	attrs := []any{slog.Any(ElapsedKey, spanElapsed(elapsed))}
+	if hasError(args) {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String(StatusKey, "error"))
+	}
+	// Строка завершения - последняя в ветке спана
+	s.emit(closeBranch(s.inner), level, append(attrs, args...))
+	return elapsed
+}
+
+// emit выводит запись спана; источником считается вызов Start или End
+func (s *Span) emit(ctx context.Context, level slog.Level, args []any) {
+	if !s.log.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // runtime.Callers, emit, Start/End
+	r := slog.NewRecord(time.Now(), level, s.name, pcs[0])
+	r.Add(args...)
+	_ = s.log.Handler().Handle(ctx, r)
+}
+
+// hasError сообщает, есть ли среди аргументов записи ошибка
+func hasError(args []any) bool {
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case error:
+			return true
+		case slog.Attr:
+			if v := a.Value.Resolve(); v.Kind() == slog.KindAny {
+				if _, ok := v.Any().(error); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// spanElapsed - длительность из Span.End. Цветной формат раскрашивает ее
+// по величине, JSON и logfmt выводят как slog.Duration. Другие handler'ы
+// получают число наносекунд в JSON и текст time.Duration в String.
+type spanElapsed time.Duration
+
+func (d spanElapsed) String() string { return time.Duration(d).String() }
+
+// spanElapsedOf возвращает длительность спана из значения атрибута
+func spanElapsedOf(v slog.Value) (time.Duration, bool) {
+	if v.Kind() != slog.KindAny {
+		return 0, false
+	}
+	d, ok := v.Any().(spanElapsed)
+	return time.Duration(d), ok
+}
+
+// elapsedStyle выбирает цвет длительности по величине
+func elapsedStyle(d time.Duration) Style {
+	switch {
+	case d >= elapsedSlow:
+		return elapsedSlowStyle
+	case d >= elapsedNoticed:
+		return elapsedNoticedStyle
+	}
+	return elapsedFastStyle
+}
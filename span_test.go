@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpan_StartEnd(t *testing.T) {
+	h, buf := newTestHandler()
+	log := slog.New(h)
+
+	span := Start(context.Background(), log, "import users", "source", "s3")
+	log.InfoContext(span.Context(), "fetched", "n", 10)
+	inner := Start(span.Context(), log, "save")
+	log.InfoContext(inner.Context(), "batch")
+	inner.End()
+	elapsed := span.End("rows", 10)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		"INF import users source=s3",
+		"INF ├─ fetched n=10",
+		"INF ├─ save",
+		"INF │  ├─ batch",
+		"INF │  └─ save elapsed=",
+		"INF └─ import users elapsed=",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("ожидалось %d строк:\n%s", len(want), buf.String())
+	}
+	for i, w := range want {
+		if line := lines[i][len("[12:30:45] "):]; !strings.HasPrefix(line, w) {
+			t.Errorf("строка %d: %q, ожидалось начало %q", i, line, w)
+		}
+	}
+	if !strings.HasSuffix(lines[5], " rows=10") {
+		t.Errorf("атрибуты End: %q", lines[5])
+	}
+	if elapsed <= 0 {
+		t.Errorf("End должен вернуть длительность: %v", elapsed)
+	}
+
+	// Повторный End ничего не выводит
+	buf.Reset()
+	span.End()
+	if buf.Len() != 0 {
+		t.Errorf("повторный End: %q", buf.String())
+	}
+}
+
+func TestSpan_Error(t *testing.T) {
+	h, buf := newTestHandler()
+	var hooked bool
+	h.SetHook(func(context.Context, slog.Record) { hooked = true })
+
+	span := Start(context.Background(), slog.New(h), "sync")
+	buf.Reset()
+	span.End("err", errors.New("timeout"))
+
+	if !strings.Contains(buf.String(), " ERR └─ sync elapsed=") || !strings.Contains(buf.String(), " status=error err=timeout") {
+		t.Errorf("строка завершения с ошибкой: %q", buf.String())
+	}
+	if !hooked {
+		t.Error("ошибка спана должна вызывать хук")
+	}
+
+	if !hasError([]any{slog.Any("err", errors.New("x"))}) || hasError([]any{"k", "v"}) {
+		t.Error("hasError распознает ошибки неверно")
+	}
+}
+
+func TestSpan_JSON(t *testing.T) {
+	h, buf := newTestHandler()
+	h.Format = FormatJSON
+	Start(context.Background(), slog.New(h), "job").End()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"msg":"job","elapsed":`) {
+		t.Fatalf("JSON: %q", buf.String())
+	}
+	// Длительность выводится числом наносекунд, как slog.Duration
+	if _, rest, _ := strings.Cut(lines[1], `"elapsed":`); rest == "" || rest[0] < '0' || rest[0] > '9' {
+		t.Errorf("elapsed в JSON должен быть числом: %q", lines[1])
+	}
+	if strings.Contains(lines[1], "└─") {
+		t.Errorf("линии дерева выводятся только в цветном формате: %q", lines[1])
+	}
+}
+
+func TestElapsedStyle(t *testing.T) {
+	withColors(t)
+	tests := []struct {
+		d    time.Duration
+		want Style
+	}{
+		{5 * time.Millisecond, elapsedFastStyle},
+		{300 * time.Millisecond, elapsedNoticedStyle},
+		{2 * time.Second, elapsedSlowStyle},
+	}
+	for _, tt := range tests {
+		h, buf := newTestHandler()
+		r := newTestRecord(slog.LevelInfo, "done")
+		r.AddAttrs(slog.Any(ElapsedKey, spanElapsed(tt.d)))
+		_ = h.Handle(context.Background(), r)
+
+		if !strings.Contains(buf.String(), string(tt.want)+tt.d.String()) {
+			t.Errorf("%v: ожидался стиль %q в %q", tt.d, tt.want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Состояния разбора escape-последовательностей в StripWriter
+const (
+	stripText   = iota // обычный текст
+	stripEsc           // получен ESC
+	stripCSI           // внутри CSI: ESC [ параметры финальный_байт
+	stripOSC           // внутри OSC: ESC ] ... BEL или ESC \
+	stripOSCEsc        // ESC внутри OSC, ожидается '\'
+)
+
+// StripWriter удаляет из потока ANSI-последовательности (SGR и прочие CSI,
+// а также OSC, включая гиперссылки OSC 8), сохраняя видимый текст.
+// Последовательность может быть разорвана между вызовами Write.
+//
+//	file := logger.NewStripWriter(f)
+//	log := slog.New(logger.NewColorHandler(io.MultiWriter(os.Stdout, file)))
+type StripWriter struct {
+	w     io.Writer
+	mu    sync.Mutex
+	state int
+}
+
+// NewStripWriter создает StripWriter, пишущий очищенный текст в w
+func NewStripWriter(w io.Writer) *StripWriter {
+	return &StripWriter{w: w}
+}
+
+// Write удаляет escape-последовательности из p и пишет остаток в исходный writer.
+// Возвращает len(p), если запись прошла успешно.
+func (s *StripWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Быстрый путь: нет ни одного ESC и мы не внутри последовательности
+	if s.state == stripText && bytes.IndexByte(p, '\x1b') < 0 {
+		if _, err := s.w.Write(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	buf := newBuffer()
+	defer buf.Free()
+	s.strip(buf, p)
+
+	if len(*buf) > 0 {
+		if _, err := s.w.Write(*buf); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// strip дописывает в buf видимый текст из p, продолжая разбор с s.state
+func (s *StripWriter) strip(buf *buffer, p []byte) {
+	for len(p) > 0 {
+		switch s.state {
+		case stripText:
+			i := bytes.IndexByte(p, '\x1b')
+			if i < 0 {
+				buf.Write(p)
+				return
+			}
+			buf.Write(p[:i])
+			p = p[i+1:]
+			s.state = stripEsc
+			continue
+
+		case stripEsc:
+			switch c := p[0]; {
+			case c == '[':
+				s.state = stripCSI
+			case c == ']':
+				s.state = stripOSC
+			case c >= 0x30 && c <= 0x7e:
+				// Двухбайтовая последовательность (ESC 7, ESC M, ESC c, ...)
+				s.state = stripText
+			default:
+				// Не escape-последовательность: оставляем как есть
+				buf.WriteByte('\x1b')
+				buf.WriteByte(c)
+				s.state = stripText
+			}
+
+		case stripCSI:
+			switch c := p[0]; {
+			case c >= 0x20 && c <= 0x3f:
+				// Параметры и промежуточные байты
+			case c >= 0x40 && c <= 0x7e:
+				// Финальный байт ('m' для SGR)
+				s.state = stripText
+			default:
+				// Оборванная последовательность: байт считаем текстом
+				buf.WriteByte(c)
+				s.state = stripText
+			}
+
+		case stripOSC:
+			i := bytes.IndexAny(p, "\x07\x1b")
+			if i < 0 {
+				return
+			}
+			if p[i] == '\x07' {
+				s.state = stripText
+			} else {
+				s.state = stripOSCEsc
+			}
+			p = p[i+1:]
+			continue
+
+		case stripOSCEsc:
+			if p[0] == '\\' {
+				s.state = stripText
+			} else {
+				s.state = stripOSC
+				continue
+			}
+		}
+		p = p[1:]
+	}
+}
+
+// Flush сбрасывает буферы исходного writer'а, если он это поддерживает
+func (s *StripWriter) Flush() error {
+	return flushWriter(s.w)
+}
+
+// Close закрывает исходный writer (кроме os.Stdout и os.Stderr)
+func (s *StripWriter) Close() error {
+	return closeWriter(s.w)
+}
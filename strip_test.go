@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// ──────────────────────────────────────────────────────────
+// StripWriter
+// ──────────────────────────────────────────────────────────
+
+func TestStripWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello world\n", "hello world\n"},
+		{"sgr", "\x1b[94m[12:30:45] \x1b[0m\x1b[32mINF \x1b[0m", "[12:30:45] INF "},
+		{"sgr_multi_params", "\x1b[1;4;38;5;208mbold\x1b[22;24m", "bold"},
+		{"osc8_bel", "see \x1b]8;;https://example.com\x07link\x1b]8;;\x07 here", "see link here"},
+		{"osc8_st", "\x1b]8;id=1;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"two_byte_escape", "a\x1bcb", "ab"},
+		{"lone_esc_kept", "a\x1b\x01b", "a\x1b\x01b"},
+		{"utf8", "\x1b[93mпривет\x1b[0m", "привет"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := NewStripWriter(&out)
+
+			n, err := w.Write([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("Write вернул ошибку: %v", err)
+			}
+			if n != len(tt.in) {
+				t.Errorf("Write вернул n=%d, ожидалось %d", n, len(tt.in))
+			}
+			if out.String() != tt.want {
+				t.Errorf("вывод = %q, ожидалось %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestStripWriter_SplitSequences(t *testing.T) {
+	in := "\x1b[94mtime\x1b[0m \x1b]8;;https://x.y\x1b\\link\x1b]8;;\x07 \x1b[1;32mok\x1b[0m\n"
+	want := "time link ok\n"
+
+	// Последовательности разрываются между вызовами Write на каждом байте
+	var out bytes.Buffer
+	w := NewStripWriter(&out)
+	for i := range len(in) {
+		_, _ = w.Write([]byte{in[i]})
+	}
+
+	if out.String() != want {
+		t.Errorf("вывод = %q, ожидалось %q", out.String(), want)
+	}
+}
+
+func TestStripWriter_ColorHandler(t *testing.T) {
+	r := newTestRecord(slog.LevelWarn, "disk usage")
+	r.AddAttrs(slog.Float64("percent", 91.4), slog.Group("mount", slog.String("path", "/data")))
+
+	var plain bytes.Buffer
+	_ = NewColorHandler(&plain).WithGroup("sys").Handle(context.Background(), r)
+
+	withColors(t)
+	var stripped bytes.Buffer
+	_ = NewColorHandler(NewStripWriter(&stripped)).WithGroup("sys").Handle(context.Background(), r)
+
+	if stripped.String() != plain.String() {
+		t.Errorf("после удаления цветов вывод отличается:\n got: %q\nwant: %q", stripped.String(), plain.String())
+	}
+}
+
+func BenchmarkStripWriter(b *testing.B) {
+	withColors(b)
+	var line bytes.Buffer
+	_ = NewColorHandler(&line).Handle(context.Background(), benchRecord())
+
+	w := NewStripWriter(io.Discard)
+	b.SetBytes(int64(line.Len()))
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = w.Write(line.Bytes())
+	}
+}
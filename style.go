@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/fatih/color"
+)
+
+// ansiReset сбрасывает все атрибуты терминала (как color.Color.Fprintf)
+const ansiReset = "\x1b[0m"
+
+// Style - заранее собранная SGR-последовательность, например "\x1b[94m".
+// Собирается один раз, чтобы не создавать color.Color на каждую запись.
+// Пустой Style означает оформление по умолчанию.
+type Style string
+
+// NewStyle собирает SGR-последовательность из атрибутов fatih/color:
+// цветов и стилей текста (color.Bold, color.Underline, color.ReverseVideo)
+func NewStyle(attrs ...color.Attribute) Style {
+	seq := []byte("\x1b[")
+	for i, a := range attrs {
+		if i > 0 {
+			seq = append(seq, ';')
+		}
+		seq = strconv.AppendInt(seq, int64(a), 10)
+	}
+	return Style(append(seq, 'm'))
+}
+
+var (
+	timeStyle  = NewStyle(color.FgHiBlue)
+	groupStyle = NewStyle(color.FgHiBlue)
+	keyStyle   = NewStyle(color.FgHiGreen)
+	valueStyle = NewStyle(color.FgHiYellow)
+)
+
+// levelFormat описывает оформление уровня логирования
+type levelFormat struct {
+	label string // метка уровня (DBG, INF, ...)
+	level Style  // цвет метки
+	msg   Style  // цвет сообщения
+}
+
+var (
+	debugFormat   = levelFormat{"DBG", NewStyle(color.FgHiCyan), NewStyle(color.FgHiCyan)}
+	infoFormat    = levelFormat{"INF", NewStyle(color.FgGreen), NewStyle(color.FgGreen)}
+	warnFormat    = levelFormat{"WRN", NewStyle(color.FgHiYellow), NewStyle(color.FgHiWhite)}
+	errorFormat   = levelFormat{"ERR", NewStyle(color.FgHiRed), NewStyle(color.FgHiWhite)}
+	unknownFormat = levelFormat{"???", NewStyle(color.FgWhite), NewStyle(color.FgHiWhite)}
+)
+
+// formatForLevel выбирает оформление в зависимости от уровня логирования
+func formatForLevel(level slog.Level) *levelFormat {
+	switch level {
+	case slog.LevelDebug:
+		return &debugFormat
+	case slog.LevelInfo:
+		return &infoFormat
+	case slog.LevelWarn:
+		return &warnFormat
+	case slog.LevelError:
+		return &errorFormat
+	default:
+		return &unknownFormat
+	}
+}
+
+// colorsEnabled сообщает, нужно ли выводить ANSI-последовательности.
+// Учитывает глобальный переключатель fatih/color.
+func colorsEnabled() bool {
+	return !color.NoColor
+}
+
+// setStyle открывает стиль, если цвета включены
+func (b *buffer) setStyle(s Style, colored bool) {
+	if colored {
+		*b = append(*b, s...)
+	}
+}
+
+// resetStyle закрывает стиль, если цвета включены
+func (b *buffer) resetStyle(colored bool) {
+	if colored {
+		*b = append(*b, ansiReset...)
+	}
+}
@@ -0,0 +1,332 @@
+package logger
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// table - значение, разобранное на колонки и строки для вывода таблицей
+type table struct {
+	header  []string
+	rows    [][]string
+	numeric []bool // колонки из одних чисел выравниваются вправо
+	total   int    // строк в исходном значении, включая не вошедшие в rows
+}
+
+// tableLimits возвращает число строк и ширину колонки с учетом значений по умолчанию
+func (h *ColorHandler) tableLimits() (rows, width int) {
+	return cmp.Or(max(h.TableMaxRows, 0), DefaultTableMaxRows),
+		cmp.Or(max(h.TableColumnWidth, 0), DefaultTableColumnWidth)
+}
+
+// tableOf разбирает значение на таблицу, если Tables включен и значение -
+// непустой однородный срез (массив) структур или map со строковыми
+// ключами, либо map таких значений. Типы с форматтером не разбираются.
+func (h *ColorHandler) tableOf(v slog.Value) (*table, bool) {
+	if !h.Tables || v.Kind() != slog.KindAny || hasFormatter(h.Formatters, v.Any()) {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v.Any())
+	maxRows, _ := h.tableLimits()
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return nil, false
+	}
+	if rv.Len() == 0 {
+		return nil, false
+	}
+
+	// Тип элементов известен заранее: срез чисел или строк отклоняется
+	// без обхода. Обходить все элементы нужно, только если среди них
+	// могут быть nil или значения разных типов.
+	elemType := rv.Type().Elem()
+	mayVary := elemType.Kind() == reflect.Pointer || elemType.Kind() == reflect.Interface
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Interface && !isRowType(elemType) {
+		return nil, false
+	}
+
+	// Все элементы должны быть одного типа: структурой или map со строковыми ключами
+	var rowType reflect.Type
+	rowOf := func(e reflect.Value) (reflect.Value, bool) {
+		e = indirect(e)
+		if !e.IsValid() || !isRowType(e.Type()) || rowType != nil && e.Type() != rowType {
+			return reflect.Value{}, false
+		}
+		rowType = e.Type()
+		return e, true
+	}
+
+	var keys []string
+	var shown []reflect.Value
+	if rv.Kind() == reflect.Map {
+		// Строки map идут в порядке ключей, ключ - первая колонка;
+		// хранятся только первые maxRows строк
+		iter := rv.MapRange()
+		for iter.Next() {
+			e, ok := rowOf(iter.Value())
+			if !ok {
+				return nil, false
+			}
+			keys, shown = insertRow(keys, shown, fmt.Sprint(iter.Key().Interface()), e, maxRows)
+		}
+	} else {
+		n := rv.Len()
+		if !mayVary {
+			n = min(n, maxRows)
+		}
+		for i := range n {
+			e, ok := rowOf(rv.Index(i))
+			if !ok {
+				return nil, false
+			}
+			if i < maxRows {
+				shown = append(shown, e)
+			}
+		}
+	}
+
+	t := &table{total: rv.Len()}
+	var columns []string
+	var fields [][]int
+	if rowType.Kind() == reflect.Struct {
+		columns, fields = structColumns(rowType)
+	} else {
+		columns = mapColumns(shown)
+	}
+	if len(columns) == 0 {
+		return nil, false
+	}
+
+	if keys != nil {
+		t.header = append(t.header, "key")
+	}
+	t.header = append(t.header, columns...)
+	t.numeric = make([]bool, len(t.header))
+	for i := range t.numeric {
+		t.numeric[i] = true
+	}
+
+	for i, e := range shown {
+		row := make([]string, 0, len(t.header))
+		numeric := make([]bool, 0, len(t.header))
+		if keys != nil {
+			row = append(row, keys[i])
+			numeric = append(numeric, false)
+		}
+		for c, name := range columns {
+			var cell reflect.Value
+			if fields != nil {
+				cell = e.FieldByIndex(fields[c])
+			} else {
+				cell = e.MapIndex(reflect.ValueOf(name).Convert(rowType.Key()))
+			}
+			text, num := cellText(cell, h.Formatters)
+			row = append(row, text)
+			numeric = append(numeric, num)
+		}
+		for c := range numeric {
+			// Пустые ячейки не мешают выравнивать числовую колонку
+			t.numeric[c] = t.numeric[c] && (numeric[c] || row[c] == "")
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t, true
+}
+
+// insertRow вставляет строку map в отсортированные по ключу keys и rows,
+// оставляя не больше limit первых
+func insertRow(keys []string, rows []reflect.Value, key string, row reflect.Value, limit int) ([]string, []reflect.Value) {
+	i, _ := slices.BinarySearch(keys, key)
+	if i >= limit {
+		return keys, rows
+	}
+	keys = slices.Insert(keys, i, key)
+	rows = slices.Insert(rows, i, row)
+	if len(keys) > limit {
+		keys, rows = keys[:limit], rows[:limit]
+	}
+	return keys, rows
+}
+
+// isRowType сообщает, можно ли вывести значение типа t строкой таблицы
+func isRowType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	}
+	return false
+}
+
+// indirect снимает указатели и интерфейсы; nil дает нулевой reflect.Value
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// structColumns возвращает экспортируемые поля структуры: имена берутся
+// из тегов json, поля с тегом "-" пропускаются
+func structColumns(t reflect.Type) (names []string, index [][]int) {
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := f.Name
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		names = append(names, name)
+		index = append(index, f.Index)
+	}
+	return names, index
+}
+
+// mapColumns возвращает отсортированное объединение ключей строк-map
+func mapColumns(rows []reflect.Value) []string {
+	var names []string
+	for _, r := range rows {
+		for _, k := range r.MapKeys() {
+			if name := k.String(); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// cellText выводит значение ячейки текстом: типы с форматтером - им,
+// скаляры - как есть, вложенные значения - компактным JSON.
+// Второй результат сообщает, что значение числовое.
+func cellText(v reflect.Value, fs []Formatter) (string, bool) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() || (v.Kind() == reflect.Pointer || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return "", false
+	}
+	if v.CanInterface() {
+		if s, ok := formatWith(fs, v.Interface()); ok {
+			return string(s), false
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), false
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true
+	}
+	if data, err := json.Marshal(v.Interface()); err == nil {
+		return string(data), false
+	}
+	return fmt.Sprint(v.Interface()), false
+}
+
+// truncateCell обрезает s до width символов, заменяя хвост многоточием
+func truncateCell(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// appendTable выводит в строке записи размер таблицы, а саму таблицу -
+// в blocks, под записью. Стиль vs уже открыт.
+func (h *ColorHandler) appendTable(buf, blocks *buffer, key string, t *table, vs Style, colored bool) {
+	buf.WriteByte('[')
+	*buf = strconv.AppendInt(*buf, int64(t.total), 10)
+	buf.WriteString(" rows]")
+
+	_, maxWidth := h.tableLimits()
+	widths := make([]int, len(t.header))
+	for c, name := range t.header {
+		t.header[c] = truncateCell(name, maxWidth)
+		widths[c] = utf8.RuneCountInString(t.header[c])
+	}
+	for _, row := range t.rows {
+		for c := range row {
+			row[c] = truncateCell(strings.ReplaceAll(row[c], "\n", " "), maxWidth)
+			widths[c] = max(widths[c], utf8.RuneCountInString(row[c]))
+		}
+	}
+
+	blocks.WriteString("\n  ")
+	blocks.setStyle(keyStyle, colored)
+	blocks.WriteString(key)
+	blocks.WriteByte(':')
+	blocks.resetStyle(colored)
+
+	// Колонки разделяются двумя пробелами; пустые ячейки в конце строки
+	// не выводятся, чтобы не оставлять хвостовых пробелов
+	appendRow := func(cells []string, s Style) {
+		last := len(cells) - 1
+		for last > 0 && cells[last] == "" {
+			last--
+		}
+		blocks.WriteString("\n  ")
+		for c, cell := range cells[:last+1] {
+			if c > 0 {
+				blocks.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[c]-utf8.RuneCountInString(cell))
+			if t.numeric[c] {
+				blocks.WriteString(pad)
+			}
+			if cell != "" {
+				blocks.setStyle(s, colored)
+				blocks.WriteString(cell)
+				blocks.resetStyle(colored)
+			}
+			if !t.numeric[c] && c < last {
+				blocks.WriteString(pad)
+			}
+		}
+	}
+
+	appendRow(t.header, keyStyle)
+	rule := make([]string, len(widths))
+	for c, w := range widths {
+		rule[c] = strings.Repeat("─", w)
+	}
+	appendRow(rule, dumpDimStyle)
+	for _, row := range t.rows {
+		appendRow(row, vs)
+	}
+
+	if rest := t.total - len(t.rows); rest > 0 {
+		blocks.WriteString("\n  ")
+		blocks.setStyle(dumpDimStyle, colored)
+		blocks.WriteString("… +")
+		*blocks = strconv.AppendInt(*blocks, int64(rest), 10)
+		blocks.WriteString(" rows")
+		blocks.resetStyle(colored)
+	}
+}
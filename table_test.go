@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type order struct {
+	ID       int     `json:"id"`
+	Customer string  `json:"customer"`
+	Total    float64 `json:"total"`
+	Secret   string  `json:"-"`
+	note     string
+}
+
+// renderTable выводит атрибут v без цветов с включенными таблицами
+func renderTable(t *testing.T, v any, configure func(h *ColorHandler)) string {
+	t.Helper()
+	h, buf := newTestHandler()
+	h.Tables = true
+	if configure != nil {
+		configure(h)
+	}
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.Any("v", v))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func TestTable_SliceOfStructs(t *testing.T) {
+	orders := []order{
+		{ID: 1, Customer: "alice", Total: 12.5, Secret: "x", note: "y"},
+		{ID: 20, Customer: "bob", Total: 3},
+	}
+	got := renderTable(t, orders, nil)
+
+	want := "[12:30:45] INF m v=[2 rows]\n" +
+		"  v:\n" +
+		"  id  customer  total\n" +
+		"  ──  ────────  ─────\n" +
+		"   1  alice      12.5\n" +
+		"  20  bob           3"
+	if got != want {
+		t.Errorf("таблица:\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestTable_MapOfStructs(t *testing.T) {
+	stats := map[string]*order{
+		"west": {ID: 2, Customer: "carol"},
+		"east": {ID: 1, Customer: "dave"},
+	}
+	got := renderTable(t, stats, nil)
+
+	want := "[12:30:45] INF m v=[2 rows]\n" +
+		"  v:\n" +
+		"  key   id  customer  total\n" +
+		"  ────  ──  ────────  ─────\n" +
+		"  east   1  dave          0\n" +
+		"  west   2  carol         0"
+	if got != want {
+		t.Errorf("таблица:\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestTable_SliceOfMaps(t *testing.T) {
+	rows := []map[string]any{
+		{"name": "a", "tags": []string{"x"}},
+		{"name": "b", "size": 3},
+	}
+	got := renderTable(t, rows, nil)
+
+	want := "[12:30:45] INF m v=[2 rows]\n" +
+		"  v:\n" +
+		"  name  size  tags\n" +
+		"  ────  ────  ─────\n" +
+		"  a           [\"x\"]\n" +
+		"  b        3"
+	if got != want {
+		t.Errorf("таблица:\n%s\nожидалось\n%s", got, want)
+	}
+}
+
+func TestTable_Limits(t *testing.T) {
+	orders := make([]order, 5)
+	for i := range orders {
+		orders[i] = order{ID: i, Customer: strings.Repeat("z", 30)}
+	}
+	got := renderTable(t, orders, func(h *ColorHandler) {
+		h.TableMaxRows = 2
+		h.TableColumnWidth = 8
+	})
+
+	lines := strings.Split(got, "\n")
+	if len(lines) != 7 {
+		t.Fatalf("ожидалось 7 строк, получено:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "[12:30:45] INF m v=[5 rows]") {
+		t.Errorf("в строке записи должно быть полное число строк: %q", lines[0])
+	}
+	if lines[4] != "   0  zzzzzzz…      0" {
+		t.Errorf("обрезка колонки: %q", lines[4])
+	}
+	if lines[6] != "  … +3 rows" {
+		t.Errorf("строка остатка: %q", lines[6])
+	}
+}
+
+func TestTable_Fallback(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"Empty", []order{}},
+		{"Scalars", []int{1, 2}},
+		{"Mixed", []any{order{}, map[string]int{}}},
+		{"NilElement", []*order{{}, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderTable(t, tt.v, nil); strings.Contains(got, "rows]") {
+				t.Errorf("значение не должно выводиться таблицей: %q", got)
+			}
+		})
+	}
+
+	// Без Tables срез выводится как раньше
+	if got := renderValue(t, FormatColor, []order{{ID: 1}}); strings.Contains(got, "rows]") {
+		t.Errorf("таблицы включаются только явно: %q", got)
+	}
+}
+
+func TestTable_MapLimit(t *testing.T) {
+	stats := map[string]order{}
+	for _, k := range []string{"e", "c", "a", "d", "b"} {
+		stats[k] = order{Customer: k}
+	}
+	got := renderTable(t, stats, func(h *ColorHandler) { h.TableMaxRows = 2 })
+
+	lines := strings.Split(got, "\n")
+	if !strings.HasPrefix(lines[0], "[12:30:45] INF m v=[5 rows]") {
+		t.Errorf("в строке записи должно быть полное число строк: %q", lines[0])
+	}
+	if len(lines) != 7 || !strings.HasPrefix(lines[4], "  a ") || !strings.HasPrefix(lines[5], "  b ") {
+		t.Errorf("должны выводиться первые по ключу строки:\n%s", got)
+	}
+}
+
+func TestTable_RejectsByElemType(t *testing.T) {
+	h := NewColorHandler(nil)
+	h.Tables = true
+	v := slog.AnyValue(make([]int, 1_000_000))
+
+	// Срез скаляров отклоняется по типу элементов, без их обхода
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, ok := h.tableOf(v); ok {
+			t.Fatal("срез чисел не таблица")
+		}
+	})
+	if allocs > 0 {
+		t.Errorf("отклонение по типу не должно аллоцировать: %v", allocs)
+	}
+}
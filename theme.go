@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"log/slog"
+
+	"github.com/fatih/color"
+)
+
+// Theme задает цвет значений атрибутов по их типу. Пустой стиль
+// означает цвет по умолчанию: FgHiYellow для значения, свой цвет для
+// каждой лексемы JSON. Нулевой Theme выводит скаляры одним цветом,
+// а в JSON-значениях подсвечивает ключи, строки, числа, true/false и null.
+type Theme struct {
+	String   Style // slog.KindString
+	Number   Style // целые и вещественные числа
+	Bool     Style
+	Duration Style
+	Time     Style
+	Nil      Style // nil в slog.Any
+	Error    Style // значения, реализующие error
+	JSON     Style // структуры, карты, срезы и json.RawMessage; в JSON - скобки и разделители
+
+	// Цвета лексем JSON-значений; пустой стиль - цвет по умолчанию для лексемы
+	JSONKey    Style
+	JSONString Style
+	JSONNumber Style
+	JSONBool   Style
+	JSONNull   Style
+}
+
+// KindTheme возвращает тему, в которой у каждого типа значений свой цвет
+func KindTheme() Theme {
+	return Theme{
+		String:   NewStyle(color.FgHiYellow),
+		Number:   NewStyle(color.FgHiCyan),
+		Bool:     NewStyle(color.FgHiMagenta),
+		Duration: NewStyle(color.FgCyan),
+		Time:     NewStyle(color.FgBlue),
+		Nil:      NewStyle(color.FgHiBlack),
+		Error:    NewStyle(color.FgHiRed),
+		JSON:     NewStyle(color.FgWhite),
+
+		JSONKey:    NewStyle(color.FgHiBlue),
+		JSONString: NewStyle(color.FgHiYellow),
+		JSONNumber: NewStyle(color.FgHiCyan),
+		JSONBool:   NewStyle(color.FgHiMagenta),
+		JSONNull:   NewStyle(color.FgHiBlack),
+	}
+}
+
+// valueStyle возвращает стиль значения v или пустой стиль; fs - форматтеры handler'а
+func (t *Theme) valueStyle(v slog.Value, fs []Formatter) Style {
+	switch v.Kind() {
+	case slog.KindString:
+		return t.String
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64:
+		return t.Number
+	case slog.KindBool:
+		return t.Bool
+	case slog.KindDuration:
+		return t.Duration
+	case slog.KindTime:
+		return t.Time
+	}
+
+	switch a := v.Any().(type) {
+	case nil:
+		return t.Nil
+	case error:
+		return t.Error
+	default:
+		// Значения с форматтером выводятся текстом
+		if hasFormatter(fs, a) {
+			return t.String
+		}
+	}
+	return t.JSON
+}
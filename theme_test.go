@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTheme_ValueStyleByKind(t *testing.T) {
+	withColors(t)
+
+	theme := KindTheme()
+	type point struct{ X, Y int }
+	tests := []struct {
+		attr slog.Attr
+		want Style
+	}{
+		{slog.String("s", "text"), theme.String},
+		{slog.Int("i", -1), theme.Number},
+		{slog.Uint64("u", 1), theme.Number},
+		{slog.Float64("f", 0.5), theme.Number},
+		{slog.Bool("b", true), theme.Bool},
+		{slog.Duration("d", time.Second), theme.Duration},
+		{slog.Time("t", time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)), theme.Time},
+		{slog.Any("n", nil), theme.Nil},
+		{slog.Any("e", errors.New("boom")), theme.Error},
+		{slog.Any("p", point{1, 2}), theme.JSON},
+		{slog.Any("m", map[string]int{"a": 1}), theme.JSON},
+	}
+	for _, tt := range tests {
+		h, buf := newTestHandler()
+		h.Theme = theme
+		r := newTestRecord(slog.LevelInfo, "m")
+		r.AddAttrs(tt.attr)
+		_ = h.Handle(context.Background(), r)
+
+		prefix := string(keyStyle) + " " + tt.attr.Key + "=" + ansiReset + string(tt.want)
+		if !strings.Contains(buf.String(), prefix) {
+			t.Errorf("%s: ожидался стиль %q в %q", tt.attr.Key, tt.want, buf.String())
+		}
+	}
+}
+
+func TestTheme_ZeroKeepsDefault(t *testing.T) {
+	withColors(t)
+
+	h, buf := newTestHandler()
+	h.Theme = Theme{Number: KindTheme().Number}
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.String("s", "x"), slog.Int("n", 1))
+	_ = h.Handle(context.Background(), r)
+
+	if !strings.Contains(buf.String(), string(valueStyle)+"x") {
+		t.Errorf("незаданный стиль должен давать цвет по умолчанию: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), string(KindTheme().Number)+"1") {
+		t.Errorf("заданный стиль не применен: %q", buf.String())
+	}
+}
+
+func TestTheme_RuleOverridesTheme(t *testing.T) {
+	withColors(t)
+
+	h, buf := newTestHandler()
+	h.Theme = KindTheme()
+	h.Rules = []Rule{{Key: "status", Match: AtLeast(500), ValueStyle: redStyle}}
+	r := newTestRecord(slog.LevelInfo, "m")
+	r.AddAttrs(slog.Int("status", 503), slog.Int("n", 1))
+	_ = h.Handle(context.Background(), r)
+
+	if !strings.Contains(buf.String(), string(redStyle)+"503") {
+		t.Errorf("правило должно иметь приоритет над темой: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), string(KindTheme().Number)+"1") {
+		t.Errorf("тема не применена к остальным атрибутам: %q", buf.String())
+	}
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+)
+
+// guideStyle - цвет линий дерева вложенных операций
+var guideStyle = NewStyle(color.FgHiBlack)
+
+// treePos - положение записи в дереве вложенных операций
+type treePos struct {
+	depth int
+	last  bool // запись закрывает ветку: └─ вместо ├─
+}
+
+// treeKey - ключ treePos в контексте
+type treeKey struct{}
+
+func treePosOf(ctx context.Context) treePos {
+	if ctx == nil {
+		return treePos{}
+	}
+	pos, _ := ctx.Value(treeKey{}).(treePos)
+	return pos
+}
+
+// Nest возвращает контекст на уровень глубже: записи с ним ColorHandler
+// выводит веткой дерева под записями с ctx. Span делает это сам.
+//
+//	log.InfoContext(ctx, "sync")
+//	log.InfoContext(logger.Nest(ctx), "users")  // ├─ users
+func Nest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, treeKey{}, treePos{depth: treePosOf(ctx).depth + 1})
+}
+
+// Depth возвращает глубину вложенности, сохраненную в контексте Nest
+func Depth(ctx context.Context) int {
+	return treePosOf(ctx).depth
+}
+
+// closeBranch помечает записи с контекстом как последние в своей ветке
+func closeBranch(ctx context.Context) context.Context {
+	pos := treePosOf(ctx)
+	pos.last = true
+	return context.WithValue(ctx, treeKey{}, pos)
+}
+
+// appendTreeGuides дописывает линии дерева перед сообщением: │ для каждого
+// объемлющего уровня и ├─ (или └─ для последней записи ветки)
+func appendTreeGuides(buf *buffer, pos treePos, colored bool) {
+	if pos.depth == 0 {
+		return
+	}
+	buf.setStyle(guideStyle, colored)
+	for range pos.depth - 1 {
+		buf.WriteString("│  ")
+	}
+	if pos.last {
+		buf.WriteString("└─ ")
+	} else {
+		buf.WriteString("├─ ")
+	}
+	buf.resetStyle(colored)
+}
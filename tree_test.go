@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNest(t *testing.T) {
+	ctx := context.Background()
+	if Depth(ctx) != 0 || Depth(Nest(Nest(ctx))) != 2 {
+		t.Errorf("глубина: %d, %d", Depth(ctx), Depth(Nest(Nest(ctx))))
+	}
+	// Вложенный в закрытую ветку контекст снова открыт
+	if pos := treePosOf(Nest(closeBranch(Nest(ctx)))); pos.last || pos.depth != 2 {
+		t.Errorf("положение после closeBranch и Nest: %+v", pos)
+	}
+}
+
+func TestTreeGuides(t *testing.T) {
+	h, buf := newTestHandler()
+	log := slog.New(h)
+
+	ctx := context.Background()
+	job := Nest(ctx)
+	step := Nest(job)
+	log.InfoContext(ctx, "job")
+	log.InfoContext(job, "step 1")
+	log.InfoContext(step, "detail")
+	log.WarnContext(closeBranch(step), "last detail")
+	log.InfoContext(closeBranch(job), "step 2")
+
+	want := []string{
+		"[12:30:45] INF job",
+		"[12:30:45] INF ├─ step 1",
+		"[12:30:45] INF │  ├─ detail",
+		"[12:30:45] WRN │  └─ last detail",
+		"[12:30:45] INF └─ step 2",
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("вывод:\n%s", buf.String())
+	}
+	for i := range want {
+		// Время берется из записи, сравниваем без него
+		if got[i][len("[12:30:45]"):] != want[i][len("[12:30:45]"):] {
+			t.Errorf("строка %d: %q, ожидалось %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTreeGuides_Colors(t *testing.T) {
+	withColors(t)
+	h, buf := newTestHandler()
+	r := newTestRecord(slog.LevelInfo, "m")
+	_ = h.Handle(Nest(context.Background()), r)
+
+	if want := string(guideStyle) + "├─ " + ansiReset + string(infoFormat.msg) + "m"; !strings.Contains(buf.String(), want) {
+		t.Errorf("линии дерева должны выводиться перед сообщением:\n%q\nожидалось\n%q", buf.String(), want)
+	}
+}